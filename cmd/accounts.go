@@ -12,11 +12,8 @@ import (
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
-	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
-	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 
-	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
@@ -173,21 +170,8 @@ var (
 			}
 
 			if npa.ParaTime != nil {
-				// Make an effort to support the height query.
-				//
-				// Note: Public gRPC endpoints do not allow this method.
-				round := client.RoundLatest
-				if h := common.GetHeight(); h != consensus.HeightLatest {
-					blk, err := c.Consensus().RootHash().GetLatestBlock(
-						ctx,
-						&roothash.RuntimeRequest{
-							RuntimeID: npa.ParaTime.Namespace(),
-							Height:    height,
-						},
-					)
-					cobra.CheckErr(err)
-					round = blk.Header.Round
-				}
+				round, err := common.GetActualRound(ctx, c, npa.ParaTime)
+				cobra.CheckErr(err)
 
 				rtRole, err := c.Runtime(npa.ParaTime).Accounts.Role(ctx, round, *addr)
 				cobra.CheckErr(err)
@@ -331,9 +315,9 @@ var (
 			}
 
 			// Parse amount.
-			// TODO: This should actually query the ParaTime (or config) to check what the consensus
-			//       layer denomination is in the ParaTime. Assume NATIVE for now.
-			amountBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, amount, types.NativeDenomination)
+			denom, err := common.GetDenomination(npa.ParaTime)
+			cobra.CheckErr(err)
+			amountBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, amount, denom)
 			cobra.CheckErr(err)
 
 			// Prepare transaction.
@@ -352,37 +336,20 @@ var (
 				return
 			}
 
-			decoder := conn.Runtime(npa.ParaTime).ConsensusAccounts
-			waitCh := common.WaitForEvent(ctx, npa.ParaTime, conn, decoder, func(ev client.DecodedEvent) interface{} {
-				ce, ok := ev.(*consensusaccounts.Event)
-				if !ok || ce.Deposit == nil {
-					return nil
-				}
-				if !ce.Deposit.From.Equal(acc.Address()) || ce.Deposit.Nonce != tx.AuthInfo.SignerInfo[0].Nonce {
-					return nil
-				}
-				return ce.Deposit
-			})
+			nonce := tx.AuthInfo.SignerInfo[0].Nonce
+			if !common.ShouldWait() {
+				common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
 
-			common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
+				fmt.Printf("Not waiting for deposit result. To check it later, run:\n  %s\n", transferStatusHint(npa, acc.Address(), nonce))
+				return
+			}
 
-			fmt.Printf("Waiting for deposit result...\n")
+			decoder := conn.Runtime(npa.ParaTime).ConsensusAccounts
+			waitCh := common.WaitForEvent(ctx, npa.ParaTime, conn, decoder, matchTransferEvent(transferKindDeposit, acc.Address(), nonce))
 
-			ev := <-waitCh
-			if ev == nil {
-				cobra.CheckErr("Failed to wait for event.")
-			}
+			common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
 
-			// Check for result.
-			switch we := ev.(*consensusaccounts.DepositEvent); we.IsSuccess() {
-			case true:
-				fmt.Printf("Deposit succeeded.\n")
-			case false:
-				cobra.CheckErr(fmt.Errorf("deposit failed with error code %d from module %s",
-					we.Error.Code,
-					we.Error.Module,
-				))
-			}
+			waitForTransferResult(npa, waitCh, transferKindDeposit, acc.Address(), nonce)
 		},
 	}
 
@@ -437,9 +404,9 @@ var (
 			common.CheckForceErr(common.CheckAddressNotReserved(cfg, addrToCheck))
 
 			// Parse amount.
-			// TODO: This should actually query the ParaTime (or config) to check what the consensus
-			//       layer denomination is in the ParaTime. Assume NATIVE for now.
-			amountBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, amount, types.NativeDenomination)
+			denom, err := common.GetDenomination(npa.ParaTime)
+			cobra.CheckErr(err)
+			amountBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, amount, denom)
 			cobra.CheckErr(err)
 
 			acc := common.LoadAccount(cfg, npa.AccountName)
@@ -470,38 +437,20 @@ var (
 				return
 			}
 
+			nonce := tx.AuthInfo.SignerInfo[0].Nonce
+			if !common.ShouldWait() {
+				common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
+
+				fmt.Printf("Not waiting for withdraw result. To check it later, run:\n  %s\n", transferStatusHint(npa, acc.Address(), nonce))
+				return
+			}
+
 			decoder := conn.Runtime(npa.ParaTime).ConsensusAccounts
-			waitCh := common.WaitForEvent(ctx, npa.ParaTime, conn, decoder, func(ev client.DecodedEvent) interface{} {
-				ce, ok := ev.(*consensusaccounts.Event)
-				if !ok || ce.Withdraw == nil {
-					return nil
-				}
-				if !ce.Withdraw.From.Equal(acc.Address()) || ce.Withdraw.Nonce != tx.AuthInfo.SignerInfo[0].Nonce {
-					return nil
-				}
-				return ce.Withdraw
-			})
+			waitCh := common.WaitForEvent(ctx, npa.ParaTime, conn, decoder, matchTransferEvent(transferKindWithdraw, acc.Address(), nonce))
 
 			common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
 
-			fmt.Printf("Waiting for withdraw result...\n")
-
-			ev := <-waitCh
-			if ev == nil {
-				cobra.CheckErr("Failed to wait for event.")
-			}
-			we := ev.(*consensusaccounts.WithdrawEvent)
-
-			// Check for result.
-			switch we.IsSuccess() {
-			case true:
-				fmt.Printf("Withdraw succeeded.\n")
-			case false:
-				cobra.CheckErr(fmt.Errorf("withdraw failed with error code %d from module %s",
-					we.Error.Code,
-					we.Error.Module,
-				))
-			}
+			waitForTransferResult(npa, waitCh, transferKindWithdraw, acc.Address(), nonce)
 		},
 	}
 
@@ -541,6 +490,7 @@ var (
 			switch npa.ParaTime {
 			case nil:
 				cobra.CheckErr(common.CheckLocalAccountIsConsensusCapable(cfg, toAddr.String()))
+				cobra.CheckErr(common.CheckConsensusDenomination(npa.Network))
 
 				// Consensus layer transfer.
 				amount, err := helpers.ParseConsensusDenomination(npa.Network, amount)
@@ -556,9 +506,9 @@ var (
 				cobra.CheckErr(err)
 			default:
 				// ParaTime transfer.
-				// TODO: This should actually query the ParaTime (or config) to check what the consensus
-				//       layer denomination is in the ParaTime. Assume NATIVE for now.
-				amountBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, amount, types.NativeDenomination)
+				denom, err := common.GetDenomination(npa.ParaTime)
+				cobra.CheckErr(err)
+				amountBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, amount, denom)
 				cobra.CheckErr(err)
 
 				// Prepare transaction.
@@ -737,6 +687,11 @@ var (
 			// Check, if to address is known to be unspendable.
 			common.CheckForceErr(common.CheckAddressNotReserved(cfg, toAddr.String()))
 
+			// Check, if the sender or the recipient is blacklisted.
+			if conn != nil && npa.ParaTime != nil {
+				common.CheckForceErr(preflightTransferStable(ctx, conn, npa, *toAddr))
+			}
+
 			acc := common.LoadAccount(cfg, npa.AccountName)
 
 			var sigTx, meta interface{}
@@ -758,79 +713,9 @@ var (
 				// cobra.CheckErr(err)
 			default:
 				// ParaTime stablecoin transfer.
-				amountBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, amount, types.NativeDenomination)
-				cobra.CheckErr(err)
-
-				// Prepare transaction.
-				tx := accounts.NewTransferTx(nil, &accounts.Transfer{
-					To:     *toAddr,
-					Amount: *amountBaseUnits,
-				})
-
-				sigTx, meta, err = common.SignParaTimeTransaction(ctx, npa, acc, conn, tx)
-				cobra.CheckErr(err)
-			}
-
-			common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
-		},
-	}
-
-	// MZ
-	// Temporarily used for faucet
-	accountsFaucetTransferCmd = &cobra.Command{
-		Use:   "fautransfer <amount> <to>",
-		Short: "Temporarily used for faucet, transfer given amount of tokens to a different account",
-		Args:  cobra.ExactArgs(2),
-		Run: func(cmd *cobra.Command, args []string) {
-			cfg := cliConfig.Global()
-			npa := common.GetNPASelection(cfg)
-			txCfg := common.GetTransactionConfig()
-			amount, to := args[0], args[1]
-
-			if npa.Account == nil {
-				cobra.CheckErr("no accounts configured in your wallet")
-			}
-
-			// When not in offline mode, connect to the given network endpoint.
-			ctx := context.Background()
-			var conn connection.Connection
-			if !txCfg.Offline {
-				var err error
-				conn, err = connection.Connect(ctx, npa.Network)
+				denom, err := common.GetDenomination(npa.ParaTime)
 				cobra.CheckErr(err)
-			}
-
-			// Resolve destination address.
-			toAddr, err := common.ResolveLocalAccountOrAddress(npa.Network, to)
-			cobra.CheckErr(err)
-
-			// Check, if to address is known to be unspendable.
-			common.CheckForceErr(common.CheckAddressNotReserved(cfg, toAddr.String()))
-
-			acc := common.LoadAccount(cfg, npa.AccountName)
-
-			var sigTx, meta interface{}
-			switch npa.ParaTime {
-			case nil:
-				cobra.CheckErr(common.CheckLocalAccountIsConsensusCapable(cfg, toAddr.String()))
-
-				// Consensus layer transfer.
-				amount, err := helpers.ParseConsensusDenomination(npa.Network, amount)
-				cobra.CheckErr(err)
-
-				// Prepare transaction.
-				tx := staking.NewTransferTx(0, nil, &staking.Transfer{
-					To:     toAddr.ConsensusAddress(),
-					Amount: *amount,
-				})
-
-				sigTx, err = common.SignConsensusTransactionFaucet(ctx, npa, acc, conn, tx)
-				cobra.CheckErr(err)
-			default:
-				// ParaTime transfer.
-				// TODO: This should actually query the ParaTime (or config) to check what the consensus
-				//       layer denomination is in the ParaTime. Assume NATIVE for now.
-				amountBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, amount, types.NativeDenomination)
+				amountBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, amount, denom)
 				cobra.CheckErr(err)
 
 				// Prepare transaction.
@@ -839,7 +724,7 @@ var (
 					Amount: *amountBaseUnits,
 				})
 
-				sigTx, meta, err = common.SignParaTimeTransactionFaucet(ctx, npa, acc, conn, tx)
+				sigTx, meta, err = common.SignParaTimeTransaction(ctx, npa, acc, conn, tx)
 				cobra.CheckErr(err)
 			}
 
@@ -1160,7 +1045,7 @@ func init() {
 	f := flag.NewFlagSet("", flag.ContinueOnError)
 	f.BoolVar(&showDelegations, "show-delegations", false, "show incoming and outgoing delegations")
 	accountsShowCmd.Flags().AddFlagSet(common.SelectorFlags)
-	accountsShowCmd.Flags().AddFlagSet(common.HeightFlag)
+	accountsShowCmd.Flags().AddFlagSet(common.RoundFlag)
 	accountsShowCmd.Flags().AddFlagSet(f)
 
 	accountsAllowCmd.Flags().AddFlagSet(common.SelectorFlags)
@@ -1168,14 +1053,19 @@ func init() {
 
 	accountsDepositCmd.Flags().AddFlagSet(common.SelectorFlags)
 	accountsDepositCmd.Flags().AddFlagSet(common.TransactionFlags)
+	accountsDepositCmd.Flags().AddFlagSet(common.DenominationFlag)
 	accountsDepositCmd.Flags().AddFlagSet(common.ForceFlag)
+	accountsDepositCmd.Flags().AddFlagSet(common.WaitFlags)
 
 	accountsWithdrawCmd.Flags().AddFlagSet(common.SelectorFlags)
 	accountsWithdrawCmd.Flags().AddFlagSet(common.TransactionFlags)
+	accountsWithdrawCmd.Flags().AddFlagSet(common.DenominationFlag)
 	accountsWithdrawCmd.Flags().AddFlagSet(common.ForceFlag)
+	accountsWithdrawCmd.Flags().AddFlagSet(common.WaitFlags)
 
 	accountsTransferCmd.Flags().AddFlagSet(common.SelectorFlags)
 	accountsTransferCmd.Flags().AddFlagSet(common.TransactionFlags)
+	accountsTransferCmd.Flags().AddFlagSet(common.DenominationFlag)
 	accountsTransferCmd.Flags().AddFlagSet(common.ForceFlag)
 
 	// GB: insert here accountsMintSTCmd.
@@ -1191,13 +1081,9 @@ func init() {
 	// MZ
 	accountsTransferStableCmd.Flags().AddFlagSet(common.SelectorFlags)
 	accountsTransferStableCmd.Flags().AddFlagSet(common.TransactionFlags)
+	accountsTransferStableCmd.Flags().AddFlagSet(common.DenominationFlag)
 	accountsTransferStableCmd.Flags().AddFlagSet(common.ForceFlag)
 
-	// MZ
-	accountsFaucetTransferCmd.Flags().AddFlagSet(common.SelectorFlags)
-	accountsFaucetTransferCmd.Flags().AddFlagSet(common.TransactionFlags)
-	accountsFaucetTransferCmd.Flags().AddFlagSet(common.ForceFlag)
-
 	accountsBurnCmd.Flags().AddFlagSet(common.SelectorFlags)
 	accountsBurnCmd.Flags().AddFlagSet(common.TransactionFlags)
 
@@ -1236,7 +1122,6 @@ func init() {
 
 	// MZ
 	accountsCmd.AddCommand(accountsTransferStableCmd)
-	accountsCmd.AddCommand(accountsFaucetTransferCmd)
 	accountsCmd.AddCommand(accountsBurnCmd)
 	accountsCmd.AddCommand(accountsDelegateCmd)
 	accountsCmd.AddCommand(accountsUndelegateCmd)
@@ -0,0 +1,556 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusTx "github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+// Layers a transfer can target.
+const (
+	layerConsensus = "consensus"
+	layerParaTime  = "paratime"
+)
+
+var batchTransferResults string
+
+// batchTransferRow is a single transfer of a batch transfer file.
+type batchTransferRow struct {
+	// Line is the line of the row, starting at 1.
+	Line         int
+	To           string
+	Amount       string
+	Denomination string
+	Layer        string
+
+	toAddr  *types.Address
+	amount  types.BaseUnits
+	fee     types.BaseUnits
+	skipErr error
+	sigTx   interface{}
+	meta    interface{}
+	nonce   uint64
+	hash    string
+	round   uint64
+	err     error
+
+	// submitted is true if the transaction was accepted for inclusion in a block.
+	submitted bool
+	// pending is true if the result of the submitted transaction was not waited for.
+	pending bool
+}
+
+// parseBatchTransfer parses a batch transfer CSV file with the columns address or name, amount,
+// optional denomination and optional layer. Rows without a layer use the given default layer. A
+// header row is skipped.
+func parseBatchTransfer(raw []byte, defaultLayer string) ([]*batchTransferRow, error) {
+	var rows []*batchTransferRow
+	r := csv.NewReader(bytes.NewReader(raw))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed batch transfer: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		if len(record) < 2 || len(record) > 4 {
+			return nil, fmt.Errorf("line %d: expected 2 to 4 fields (address, amount[, denomination[, layer]]), got %d", line, len(record))
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		if len(rows) == 0 && line == 1 && strings.EqualFold(record[1], "amount") {
+			// Header row.
+			continue
+		}
+
+		row := &batchTransferRow{
+			Line:   line,
+			To:     record[0],
+			Amount: record[1],
+			Layer:  defaultLayer,
+		}
+		if len(record) > 2 {
+			row.Denomination = record[2]
+		}
+		if len(record) > 3 && record[3] != "" {
+			row.Layer = strings.ToLower(record[3])
+		}
+		switch {
+		case row.To == "":
+			return nil, fmt.Errorf("line %d: missing recipient", line)
+		case row.Amount == "":
+			return nil, fmt.Errorf("line %d: missing amount", line)
+		case row.Layer != layerConsensus && row.Layer != layerParaTime:
+			return nil, fmt.Errorf("line %d: bad layer '%s' (expected %s or %s)", line, row.Layer, layerConsensus, layerParaTime)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("batch transfer is empty")
+	}
+	return rows, nil
+}
+
+// formatAmount formats the given amount on the layer of the row.
+func (row *batchTransferRow) formatAmount(npa *common.NPASelection, amount types.BaseUnits) string {
+	if row.Layer == layerConsensus {
+		return helpers.FormatConsensusDenomination(npa.Network, amount.Amount)
+	}
+	return helpers.FormatParaTimeDenomination(npa.ParaTime, amount)
+}
+
+// batchTransferTotals sums up amounts and fees per layer and denomination.
+type batchTransferTotals struct {
+	keys    []string
+	amounts map[string]*types.BaseUnits
+	fees    map[string]*types.BaseUnits
+	rows    map[string]*batchTransferRow
+}
+
+func newBatchTransferTotals() *batchTransferTotals {
+	return &batchTransferTotals{
+		amounts: make(map[string]*types.BaseUnits),
+		fees:    make(map[string]*types.BaseUnits),
+		rows:    make(map[string]*batchTransferRow),
+	}
+}
+
+func (t *batchTransferTotals) add(dst map[string]*types.BaseUnits, row *batchTransferRow, amount types.BaseUnits) error {
+	key := row.Layer + "/" + string(amount.Denomination)
+	if _, ok := t.rows[key]; !ok {
+		t.keys = append(t.keys, key)
+		t.rows[key] = row
+		zero := types.NewBaseUnits(*quantity.NewQuantity(), amount.Denomination)
+		t.amounts[key] = &zero
+		zeroFee := zero
+		t.fees[key] = &zeroFee
+	}
+	return dst[key].Amount.Add(&amount.Amount)
+}
+
+var accountsBatchTransferCmd = &cobra.Command{
+	Use:   "batch-transfer <payouts.csv>",
+	Short: "Transfer tokens to multiple recipients from a CSV file",
+	Long: "Transfer tokens to multiple recipients. Each CSV row contains the recipient address or name, " +
+		"the amount and optionally the denomination and the layer (consensus or paratime). Rows without a " +
+		"layer use the ParaTime if one is selected.\n\n" +
+		"The account is unlocked once and all transactions are signed with locally incremented nonces " +
+		"before a single summary with totals and fees is shown. Nothing is submitted before the summary is " +
+		"confirmed. Rows failing the checks, including repeated recipients, are skipped unless --force is " +
+		"given. All transactions are submitted at once and their results are awaited afterwards. The " +
+		"outcome of each row is written to a results CSV.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+		txCfg := common.GetTransactionConfig()
+
+		if npa.Account == nil {
+			cobra.CheckErr("no accounts configured in your wallet")
+		}
+		if txCfg.Offline {
+			cobra.CheckErr("batch-transfer is not available in offline mode")
+		}
+
+		defaultLayer := layerConsensus
+		if npa.ParaTime != nil {
+			defaultLayer = layerParaTime
+		}
+		raw, err := ioutil.ReadFile(args[0])
+		cobra.CheckErr(err)
+		rows, err := parseBatchTransfer(raw, defaultLayer)
+		cobra.CheckErr(err)
+
+		resultsFile := batchTransferResults
+		if resultsFile == "" {
+			resultsFile = strings.TrimSuffix(args[0], filepath.Ext(args[0])) + ".results.csv"
+		}
+
+		// Resolve recipients and amounts and run the checks on every row.
+		recipients := make(map[string]int)
+		for _, row := range rows {
+			row.toAddr, err = common.ResolveLocalAccountOrAddress(npa.Network, row.To)
+			if err != nil {
+				cobra.CheckErr(fmt.Errorf("line %d: %w", row.Line, err))
+			}
+
+			switch row.Layer {
+			case layerConsensus:
+				if row.Denomination != "" && row.Denomination != npa.Network.Denomination.Symbol {
+					cobra.CheckErr(fmt.Errorf("line %d: denomination '%s' is not available on the consensus layer", row.Line, row.Denomination))
+				}
+				amount, err := helpers.ParseConsensusDenomination(npa.Network, row.Amount)
+				if err != nil {
+					cobra.CheckErr(fmt.Errorf("line %d: %w", row.Line, err))
+				}
+				row.amount = types.NewBaseUnits(*amount, types.NativeDenomination)
+
+				// Consensus capability is not a safety check that can be overridden.
+				row.skipErr = common.CheckLocalAccountIsConsensusCapable(cfg, row.toAddr.String())
+			case layerParaTime:
+				if npa.ParaTime == nil {
+					cobra.CheckErr(fmt.Errorf("line %d: no runtime configured", row.Line))
+				}
+				denom, err := common.ResolveDenomination(npa.ParaTime, row.Denomination)
+				if err != nil {
+					cobra.CheckErr(fmt.Errorf("line %d: %w", row.Line, err))
+				}
+				amount, err := helpers.ParseParaTimeDenomination(npa.ParaTime, row.Amount, denom)
+				if err != nil {
+					cobra.CheckErr(fmt.Errorf("line %d: %w", row.Line, err))
+				}
+				row.amount = *amount
+			}
+
+			if err = common.CheckAddressNotReserved(cfg, row.toAddr.String()); err != nil && !common.IsForce() && row.skipErr == nil {
+				row.skipErr = err
+			}
+
+			// Paying the same recipient twice is usually a mistake in the payout file.
+			key := fmt.Sprintf("%s/%s/%s", row.Layer, row.toAddr, row.amount.Denomination)
+			if line, ok := recipients[key]; ok {
+				if !common.IsForce() && row.skipErr == nil {
+					row.skipErr = fmt.Errorf("duplicate recipient of line %d", line)
+				}
+			} else {
+				recipients[key] = row.Line
+			}
+		}
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		// Unlock the account once and sign all transactions with locally sequenced nonces, taking
+		// transactions still pending from earlier invocations into account.
+		acc := common.LoadAccount(cfg, npa.AccountName)
+		nonceLayers := map[string]string{layerConsensus: common.NonceLayerConsensus}
+		if npa.ParaTime != nil {
+			nonceLayers[layerParaTime] = common.NonceLayer(npa)
+		}
+		nonces := make(map[string]uint64)
+		for _, row := range rows {
+			if _, ok := nonces[row.Layer]; ok || row.skipErr != nil {
+				continue
+			}
+			nonces[row.Layer], err = common.NextNonce(ctx, npa, conn, nonceLayers[row.Layer], acc.Address())
+			cobra.CheckErr(err)
+		}
+
+		for _, row := range rows {
+			if row.skipErr != nil {
+				continue
+			}
+
+			switch row.Layer {
+			case layerConsensus:
+				tx := staking.NewTransferTx(0, nil, &staking.Transfer{
+					To:     row.toAddr.ConsensusAddress(),
+					Amount: row.amount.Amount,
+				})
+				var sigTx *consensusTx.SignedTransaction
+				if sigTx, row.err = common.SignConsensusTransactionWithNonce(ctx, npa, acc, conn, tx, nonces[row.Layer]); row.err == nil {
+					row.sigTx = sigTx
+					row.nonce = nonces[row.Layer]
+					row.hash = sigTx.Hash().String()
+					row.fee = types.NewBaseUnits(tx.Fee.Amount, types.NativeDenomination)
+					nonces[row.Layer]++
+				}
+			case layerParaTime:
+				tx := accounts.NewTransferTx(nil, &accounts.Transfer{
+					To:     *row.toAddr,
+					Amount: row.amount,
+				})
+				var sigTx *types.UnverifiedTransaction
+				if sigTx, row.meta, row.err = common.SignParaTimeTransactionWithNonce(ctx, npa, acc, conn, tx, nonces[row.Layer]); row.err == nil {
+					row.sigTx = sigTx
+					row.nonce = nonces[row.Layer]
+					row.hash = sigTx.Hash().String()
+					row.fee = tx.AuthInfo.Fee.Amount
+					nonces[row.Layer]++
+				}
+			}
+		}
+
+		// Show the consolidated summary.
+		totals := newBatchTransferTotals()
+		var pending int
+		output := make([][]string, 0, len(rows))
+		for _, row := range rows {
+			check, fee := "ok", ""
+			switch {
+			case row.skipErr != nil:
+				check = "skip: " + row.skipErr.Error()
+			case row.err != nil:
+				check = "failed: " + row.err.Error()
+			default:
+				pending++
+				fee = row.formatAmount(npa, row.fee)
+				cobra.CheckErr(totals.add(totals.amounts, row, row.amount))
+				cobra.CheckErr(totals.add(totals.fees, row, row.fee))
+			}
+			output = append(output, []string{
+				fmt.Sprintf("%d", row.Line),
+				row.To,
+				row.Layer,
+				row.formatAmount(npa, row.amount),
+				fee,
+				check,
+			})
+		}
+		renderTable([]string{"Line", "Recipient", "Layer", "Amount", "Fee", "Check"}, output)
+
+		if pending == 0 {
+			cobra.CheckErr("no transfers left to submit")
+		}
+		output = make([][]string, 0, len(totals.keys))
+		for _, key := range totals.keys {
+			row := totals.rows[key]
+			output = append(output, []string{
+				row.Layer,
+				row.formatAmount(npa, *totals.amounts[key]),
+				row.formatAmount(npa, *totals.fees[key]),
+			})
+		}
+		fmt.Println()
+		fmt.Println("Totals:")
+		renderTable([]string{"Layer", "Amount", "Fees"}, output)
+
+		common.Confirm(fmt.Sprintf("Submit %d transfer(s) from account '%s'?", pending, npa.AccountName), "transfers aborted")
+
+		// Watch blocks before submitting so that no results are missed.
+		var (
+			waitCtx context.Context
+			cancel  context.CancelFunc
+		)
+		switch timeout := common.GetWaitTimeout(); timeout {
+		case 0:
+			waitCtx, cancel = context.WithCancel(ctx)
+		default:
+			waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		defer cancel()
+		var (
+			consensusBlocks <-chan *consensus.Block
+			runtimeBlocks   <-chan *roothash.AnnotatedBlock
+		)
+		if _, ok := nonces[layerConsensus]; ok && common.ShouldWait() {
+			ch, sub, err := conn.Consensus().WatchBlocks(waitCtx)
+			cobra.CheckErr(err)
+			defer sub.Close()
+			consensusBlocks = ch
+		}
+		if _, ok := nonces[layerParaTime]; ok && common.ShouldWait() {
+			ch, sub, err := conn.Runtime(npa.ParaTime).WatchBlocks(waitCtx)
+			cobra.CheckErr(err)
+			defer sub.Close()
+			runtimeBlocks = ch
+		}
+
+		// Submit the transactions in nonce order without waiting for each of them to be executed.
+		// Once a transaction was not accepted, later transactions on the same layer would have a
+		// nonce gap and are not submitted.
+		stalled := make(map[string]bool)
+		inFlight := make(map[string]*batchTransferRow)
+		for _, row := range rows {
+			if row.skipErr != nil || row.err != nil {
+				continue
+			}
+			if stalled[row.Layer] {
+				row.err = fmt.Errorf("not submitted after an earlier %s transaction failed", row.Layer)
+				continue
+			}
+
+			fmt.Printf("Submitting transfer of %s to %s...\n", row.formatAmount(npa, row.amount), row.To)
+			switch sigTx := row.sigTx.(type) {
+			case *consensusTx.SignedTransaction:
+				row.err = conn.Consensus().SubmitTxNoWait(ctx, sigTx)
+			case *types.UnverifiedTransaction:
+				row.err = conn.Runtime(npa.ParaTime).SubmitTxNoWait(ctx, sigTx)
+			}
+			if row.err != nil {
+				stalled[row.Layer] = true
+				continue
+			}
+			common.RecordTrackedNonce(npa, nonceLayers[row.Layer], acc.Address(), row.nonce)
+			row.submitted = true
+			inFlight[row.hash] = row
+		}
+
+		// Wait for the results of all submitted transactions.
+		if common.ShouldWait() {
+			fmt.Printf("Waiting for the results of %d transaction(s)...\n", len(inFlight))
+			waitErr := waitBatchTransferResults(waitCtx, conn, npa, consensusBlocks, runtimeBlocks, inFlight)
+			for _, row := range inFlight {
+				row.err = fmt.Errorf("no result seen while waiting")
+				if waitErr != nil {
+					row.err = fmt.Errorf("failed to wait for the result: %w", waitErr)
+				}
+			}
+		} else {
+			for _, row := range inFlight {
+				row.pending = true
+			}
+		}
+
+		var failed int
+		for _, row := range rows {
+			if row.skipErr == nil && row.err != nil {
+				failed++
+			}
+		}
+
+		cobra.CheckErr(writeBatchTransferResults(resultsFile, rows))
+		fmt.Printf("Results written to %s.\n", resultsFile)
+
+		if failed > 0 {
+			cobra.CheckErr(fmt.Errorf("%d of %d transfer(s) failed", failed, pending))
+		}
+	},
+}
+
+// waitBatchTransferResults waits for the results of the given in-flight transactions keyed by
+// transaction hash, removing each transaction from the map once its result is seen. Transactions
+// still in the map when the context is done did not produce a result.
+func waitBatchTransferResults(
+	ctx context.Context,
+	conn connection.Connection,
+	npa *common.NPASelection,
+	consensusBlocks <-chan *consensus.Block,
+	runtimeBlocks <-chan *roothash.AnnotatedBlock,
+	inFlight map[string]*batchTransferRow,
+) error {
+	for len(inFlight) > 0 && (consensusBlocks != nil || runtimeBlocks != nil) {
+		select {
+		case <-ctx.Done():
+			return nil
+		case blk, ok := <-consensusBlocks:
+			if !ok {
+				consensusBlocks = nil
+				continue
+			}
+			txs, err := conn.Consensus().GetTransactionsWithResults(ctx, blk.Height)
+			if err != nil {
+				return fmt.Errorf("failed to query transactions at height %d: %w", blk.Height, err)
+			}
+			for i, raw := range txs.Transactions {
+				row := inFlight[hash.NewFromBytes(raw).String()]
+				if row == nil || row.Layer != layerConsensus {
+					continue
+				}
+				if result := txs.Results[i]; !result.IsSuccess() {
+					row.err = fmt.Errorf("execution failed with error: module: %s code: %d message: %s", result.Error.Module, result.Error.Code, result.Error.Message)
+				}
+				delete(inFlight, row.hash)
+			}
+		case blk, ok := <-runtimeBlocks:
+			if !ok {
+				runtimeBlocks = nil
+				continue
+			}
+			round := blk.Block.Header.Round
+			txs, err := conn.Runtime(npa.ParaTime).GetTransactionsWithResults(ctx, round)
+			if err != nil {
+				return fmt.Errorf("failed to query transactions at round %d: %w", round, err)
+			}
+			for _, txr := range txs {
+				row := inFlight[txr.Tx.Hash().String()]
+				if row == nil || row.Layer != layerParaTime {
+					continue
+				}
+				row.round = round
+				row.err = common.DecodeParaTimeResult(&txr.Result, row.meta, nil)
+				delete(inFlight, row.hash)
+			}
+		}
+	}
+	return nil
+}
+
+// writeBatchTransferResults writes the outcome of each row to the given CSV file.
+func writeBatchTransferResults(fn string, rows []*batchTransferRow) error {
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"line", "address", "amount", "denomination", "layer", "status", "tx_hash", "round", "error"})
+	for _, row := range rows {
+		status, errMsg := "ok", ""
+		switch {
+		case row.skipErr != nil:
+			status, errMsg = "skipped", row.skipErr.Error()
+		case row.err != nil:
+			status, errMsg = "failed", row.err.Error()
+		case row.pending:
+			status = "pending"
+		}
+		hash := row.hash
+		if !row.submitted {
+			hash = ""
+		}
+		var round string
+		if row.round != 0 {
+			round = fmt.Sprintf("%d", row.round)
+		}
+		_ = w.Write([]string{
+			fmt.Sprintf("%d", row.Line),
+			row.To,
+			row.Amount,
+			row.Denomination,
+			row.Layer,
+			status,
+			hash,
+			round,
+			errMsg,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func init() {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.StringVar(&batchTransferResults, "results", "", "results CSV file (default: <payouts>.results.csv)")
+
+	accountsBatchTransferCmd.Flags().AddFlagSet(common.SelectorFlags)
+	accountsBatchTransferCmd.Flags().AddFlagSet(common.TransactionFlags)
+	accountsBatchTransferCmd.Flags().AddFlagSet(common.ForceFlag)
+	accountsBatchTransferCmd.Flags().AddFlagSet(common.WaitFlags)
+	accountsBatchTransferCmd.Flags().AddFlagSet(f)
+
+	accountsCmd.AddCommand(accountsBatchTransferCmd)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBatchTransfer(t *testing.T) {
+	require := require.New(t)
+
+	rows, err := parseBatchTransfer([]byte("address,amount,denomination,layer\nalice,1.5\nbob, 2 ,USDT\n# comment\ncarol,3,,Consensus\n"), layerParaTime)
+	require.NoError(err)
+	require.Len(rows, 3)
+	require.Equal("alice", rows[0].To)
+	require.Equal("1.5", rows[0].Amount)
+	require.Equal("", rows[0].Denomination)
+	require.Equal(layerParaTime, rows[0].Layer)
+	require.Equal(2, rows[0].Line)
+	require.Equal("2", rows[1].Amount)
+	require.Equal("USDT", rows[1].Denomination)
+	require.Equal(layerConsensus, rows[2].Layer)
+	require.Equal(5, rows[2].Line)
+
+	_, err = parseBatchTransfer([]byte("alice\n"), layerParaTime)
+	require.ErrorContains(err, "line 1: expected 2 to 4 fields")
+	_, err = parseBatchTransfer([]byte("alice,\n"), layerParaTime)
+	require.ErrorContains(err, "line 1: missing amount")
+	_, err = parseBatchTransfer([]byte("alice,1,,evm\n"), layerParaTime)
+	require.ErrorContains(err, "line 1: bad layer 'evm'")
+	_, err = parseBatchTransfer([]byte(",1\n"), layerParaTime)
+	require.ErrorContains(err, "line 1: missing recipient")
+	_, err = parseBatchTransfer([]byte("address,amount\n"), layerParaTime)
+	require.ErrorContains(err, "batch transfer is empty")
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+var nonceReset bool
+
+var accountsNonceCmd = &cobra.Command{
+	Use:   "nonce [address]",
+	Short: "Show or reset the locally tracked nonce of an account",
+	Long: "Show the on-chain nonce of the selected account (or the given account or address) together " +
+		"with the nonce that will be used for its next transaction, which takes transactions still " +
+		"pending into account. Use --reset to discard the locally tracked nonce, e.g. after pending " +
+		"transactions were dropped. Use --no-runtime for the consensus layer.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+
+		var target string
+		switch {
+		case len(args) > 0:
+			target = args[0]
+		case npa.Account != nil:
+			target = npa.Account.Address
+		default:
+			cobra.CheckErr("no address given and no wallet configured")
+		}
+		addr, err := common.ResolveLocalAccountOrAddress(npa.Network, target)
+		cobra.CheckErr(err)
+		layer := common.NonceLayer(npa)
+
+		if nonceReset {
+			cobra.CheckErr(common.ResetTrackedNonce(npa, layer, *addr))
+			fmt.Printf("Reset the tracked %s nonce of %s.\n", layer, addr)
+			return
+		}
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		var chainNonce uint64
+		switch npa.ParaTime {
+		case nil:
+			chainNonce, err = conn.Consensus().GetSignerNonce(ctx, &consensus.GetSignerNonceRequest{
+				AccountAddress: addr.ConsensusAddress(),
+				Height:         consensus.HeightLatest,
+			})
+		default:
+			chainNonce, err = conn.Runtime(npa.ParaTime).Accounts.Nonce(ctx, client.RoundLatest, *addr)
+		}
+		cobra.CheckErr(err)
+
+		entry, err := common.GetTrackedNonce(npa, layer, *addr)
+		cobra.CheckErr(err)
+
+		fmt.Printf("Address:        %s\n", addr)
+		fmt.Printf("Layer:          %s\n", layer)
+		fmt.Printf("On-chain nonce: %d\n", chainNonce)
+		fmt.Printf("Next nonce:     %d\n", entry.Reconcile(chainNonce, time.Now()))
+		switch {
+		case entry == nil:
+			fmt.Printf("Tracked nonce:  (none)\n")
+		default:
+			fmt.Printf("Tracked nonce:  %d (last used %s)\n", entry.Next, entry.Updated.Local().Format("2006-01-02 15:04:05"))
+			if entry.Next > chainNonce {
+				fmt.Printf("Pending:        %d\n", entry.Next-chainNonce)
+			}
+		}
+	},
+}
+
+func init() {
+	nonceFlags := flag.NewFlagSet("", flag.ContinueOnError)
+	nonceFlags.BoolVar(&nonceReset, "reset", false, "discard the locally tracked nonce")
+
+	accountsNonceCmd.Flags().AddFlagSet(common.SelectorFlags)
+	accountsNonceCmd.Flags().AddFlagSet(nonceFlags)
+
+	accountsCmd.AddCommand(accountsNonceCmd)
+}
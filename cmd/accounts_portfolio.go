@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+const (
+	portfolioKindGeneral   = "general"
+	portfolioKindEscrow    = "escrow"
+	portfolioKindDebonding = "debonding"
+	portfolioKindBalance   = "balance"
+
+	// portfolioMaxConcurrency is the maximum number of concurrently running queries.
+	portfolioMaxConcurrency = 8
+)
+
+// portfolioBalance is a single balance of a wallet account.
+type portfolioBalance struct {
+	Account      string `json:"account"`
+	Address      string `json:"address"`
+	Layer        string `json:"layer"`
+	Kind         string `json:"kind"`
+	Denomination string `json:"denomination"`
+	Amount       string `json:"amount"`
+
+	amount   quantity.Quantity
+	decimals uint8
+}
+
+// portfolioTotal is the total of all balances in a denomination.
+type portfolioTotal struct {
+	Denomination string `json:"denomination"`
+	Amount       string `json:"amount"`
+}
+
+// portfolioError is a failed query of the balances of a wallet account.
+type portfolioError struct {
+	Account string `json:"account"`
+	Layer   string `json:"layer"`
+	Error   string `json:"error"`
+}
+
+// portfolioReport contains the balances of wallet accounts across all layers of a network.
+type portfolioReport struct {
+	Network  string              `json:"network"`
+	Height   int64               `json:"height"`
+	Rounds   map[string]uint64   `json:"rounds,omitempty"`
+	Balances []*portfolioBalance `json:"balances"`
+	Totals   []*portfolioTotal   `json:"totals"`
+	Errors   []*portfolioError   `json:"errors,omitempty"`
+}
+
+func newPortfolioBalance(account, address, layer, kind, symbol string, amount quantity.Quantity, decimals uint8) *portfolioBalance {
+	return &portfolioBalance{
+		Account:      account,
+		Address:      address,
+		Layer:        layer,
+		Kind:         kind,
+		Denomination: symbol,
+		Amount:       prettyprint.QuantityFrac(amount, decimals),
+		amount:       amount,
+		decimals:     decimals,
+	}
+}
+
+// portfolioTotals sums up the given balances per denomination symbol. Balances of the same symbol
+// with different decimals (e.g. in the consensus layer and an EVM ParaTime) are summed up exactly
+// using the larger number of decimals.
+func portfolioTotals(balances []*portfolioBalance) []*portfolioTotal {
+	type total struct {
+		amount   *big.Int
+		decimals uint8
+	}
+	scale := func(v *big.Int, by uint8) *big.Int {
+		return v.Mul(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(by)), nil))
+	}
+
+	totals := make(map[string]*total)
+	for _, b := range balances {
+		amount := b.amount.ToBigInt()
+		t, ok := totals[b.Denomination]
+		switch {
+		case !ok:
+			totals[b.Denomination] = &total{amount: amount, decimals: b.decimals}
+			continue
+		case b.decimals > t.decimals:
+			scale(t.amount, b.decimals-t.decimals)
+			t.decimals = b.decimals
+		case b.decimals < t.decimals:
+			scale(amount, t.decimals-b.decimals)
+		}
+		t.amount.Add(t.amount, amount)
+	}
+
+	result := make([]*portfolioTotal, 0, len(totals))
+	for _, symbol := range sortedKeys(totals) {
+		var q quantity.Quantity
+		_ = q.FromBigInt(totals[symbol].amount)
+		result = append(result, &portfolioTotal{
+			Denomination: symbol,
+			Amount:       prettyprint.QuantityFrac(q, totals[symbol].decimals),
+		})
+	}
+	return result
+}
+
+// portfolioConsensusBalances queries the general, escrow and debonding balances of the given
+// account in the consensus layer.
+func portfolioConsensusBalances(ctx context.Context, conn connection.Connection, net *config.Network, height int64, name string, addr types.Address) ([]*portfolioBalance, error) {
+	query := &staking.OwnerQuery{
+		Owner:  addr.ConsensusAddress(),
+		Height: height,
+	}
+	account, err := conn.Consensus().Staking().Account(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	delegations, err := conn.Consensus().Staking().DelegationInfosFor(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	debondingDelegations, err := conn.Consensus().Staking().DebondingDelegationInfosFor(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var escrow, debonding quantity.Quantity
+	for _, di := range delegations {
+		amount, err := di.Pool.StakeForShares(&di.Shares)
+		if err != nil {
+			return nil, err
+		}
+		_ = escrow.Add(amount)
+	}
+	for _, dis := range debondingDelegations {
+		for _, di := range dis {
+			amount, err := di.Pool.StakeForShares(&di.Shares)
+			if err != nil {
+				return nil, err
+			}
+			_ = debonding.Add(amount)
+		}
+	}
+
+	layer, symbol, decimals := layerConsensus, net.Denomination.Symbol, net.Denomination.Decimals
+	return []*portfolioBalance{
+		newPortfolioBalance(name, addr.String(), layer, portfolioKindGeneral, symbol, account.General.Balance, decimals),
+		newPortfolioBalance(name, addr.String(), layer, portfolioKindEscrow, symbol, escrow, decimals),
+		newPortfolioBalance(name, addr.String(), layer, portfolioKindDebonding, symbol, debonding, decimals),
+	}, nil
+}
+
+// portfolioParaTimeBalances queries the balances of the given account in the given ParaTime at the
+// given round.
+func portfolioParaTimeBalances(ctx context.Context, conn connection.Connection, ptName string, pt *config.ParaTime, round uint64, name string, addr types.Address) ([]*portfolioBalance, error) {
+	balances, err := conn.Runtime(pt).Accounts.Balances(ctx, round, addr)
+	if err != nil {
+		return nil, err
+	}
+	if balances.Balances == nil {
+		balances.Balances = make(map[types.Denomination]types.Quantity)
+	}
+	if _, ok := balances.Balances[types.NativeDenomination]; !ok {
+		balances.Balances[types.NativeDenomination] = types.Quantity{}
+	}
+
+	result := make([]*portfolioBalance, 0, len(balances.Balances))
+	for denom, amount := range balances.Balances {
+		di := pt.GetDenominationInfo(denom)
+		result = append(result, newPortfolioBalance(name, addr.String(), ptName, portfolioKindBalance, di.Symbol, amount, di.Decimals))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Denomination < result[j].Denomination })
+	return result, nil
+}
+
+var accountsPortfolioCmd = &cobra.Command{
+	Use:   "portfolio [account...]",
+	Short: "Show the balances of wallet accounts across all layers",
+	Long: "Show the consensus general, escrow and debonding balances and the balances in every ParaTime " +
+		"configured for the network of all wallet accounts (or the given ones) at the same consensus height " +
+		"(see --height), followed by the totals per denomination. Use --no-runtime to only show consensus " +
+		"layer balances.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+		format := common.GetTabularOutputFormat()
+
+		names := args
+		if len(names) == 0 {
+			names = sortedKeys(cfg.Wallet.All)
+		}
+		if len(names) == 0 {
+			cobra.CheckErr("no accounts configured in your wallet")
+		}
+		addrs := make([]*types.Address, 0, len(names))
+		for _, name := range names {
+			acc, ok := cfg.Wallet.All[name]
+			if !ok {
+				cobra.CheckErr(fmt.Errorf("account '%s' does not exist in the wallet", name))
+			}
+			addr, err := common.ResolveLocalAccountOrAddress(npa.Network, acc.Address)
+			cobra.CheckErr(err)
+			addrs = append(addrs, addr)
+		}
+
+		var ptNames []string
+		if npa.ParaTime != nil {
+			ptNames = sortedKeys(npa.Network.ParaTimes.All)
+		}
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		height, err := common.GetActualHeight(ctx, conn.Consensus())
+		cobra.CheckErr(err)
+
+		// Query every ParaTime at its latest round at the consensus height, so that all balances
+		// are taken from the same snapshot.
+		rounds := make(map[string]uint64)
+		roundErrs := make(map[string]error)
+		for _, ptName := range ptNames {
+			rounds[ptName], roundErrs[ptName] = common.RuntimeRoundAt(ctx, conn, npa.Network.ParaTimes.All[ptName], height)
+		}
+
+		// Run the queries of all accounts and layers concurrently, keeping the results in order.
+		layers := append([]string{layerConsensus}, ptNames...)
+		results := make([][]*portfolioBalance, len(names)*len(layers))
+		errs := make([]error, len(results))
+		sem := make(chan struct{}, portfolioMaxConcurrency)
+		var wg sync.WaitGroup
+		for i, name := range names {
+			for j, layer := range layers {
+				idx := i*len(layers) + j
+				name, addr, layer := name, *addrs[i], layer
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					sem <- struct{}{}
+					defer func() { <-sem }()
+
+					switch {
+					case layer == layerConsensus:
+						results[idx], errs[idx] = portfolioConsensusBalances(ctx, conn, npa.Network, height, name, addr)
+					case roundErrs[layer] != nil:
+						errs[idx] = roundErrs[layer]
+					default:
+						results[idx], errs[idx] = portfolioParaTimeBalances(ctx, conn, layer, npa.Network.ParaTimes.All[layer], rounds[layer], name, addr)
+					}
+				}()
+			}
+		}
+		wg.Wait()
+
+		report := &portfolioReport{
+			Network:  npa.NetworkName,
+			Height:   height,
+			Rounds:   make(map[string]uint64),
+			Balances: []*portfolioBalance{},
+		}
+		for _, ptName := range ptNames {
+			if roundErrs[ptName] == nil {
+				report.Rounds[ptName] = rounds[ptName]
+			}
+		}
+		for idx, balances := range results {
+			if errs[idx] != nil {
+				report.Errors = append(report.Errors, &portfolioError{
+					Account: names[idx/len(layers)],
+					Layer:   layers[idx%len(layers)],
+					Error:   errs[idx].Error(),
+				})
+				continue
+			}
+			report.Balances = append(report.Balances, balances...)
+		}
+		report.Totals = portfolioTotals(report.Balances)
+
+		switch format {
+		case common.FormatJSON, common.FormatYAML:
+			common.PrintStructured(format, report)
+		case common.FormatCSV:
+			w := csv.NewWriter(os.Stdout)
+			cobra.CheckErr(w.Write([]string{"account", "address", "layer", "kind", "denomination", "amount"}))
+			for _, b := range report.Balances {
+				cobra.CheckErr(w.Write([]string{b.Account, b.Address, b.Layer, b.Kind, b.Denomination, b.Amount}))
+			}
+			for _, t := range report.Totals {
+				cobra.CheckErr(w.Write([]string{"", "", "", "total", t.Denomination, t.Amount}))
+			}
+			w.Flush()
+			cobra.CheckErr(w.Error())
+		default:
+			output := make([][]string, 0, len(report.Balances))
+			for _, b := range report.Balances {
+				output = append(output, []string{b.Account, b.Layer, b.Kind, b.Amount, b.Denomination})
+			}
+			renderTable([]string{"Account", "Layer", "Kind", "Amount", "Denomination"}, output)
+			fmt.Println()
+
+			output = make([][]string, 0, len(report.Totals))
+			for _, t := range report.Totals {
+				output = append(output, []string{t.Denomination, t.Amount})
+			}
+			fmt.Println("Totals:")
+			renderTable([]string{"Denomination", "Amount"}, output)
+		}
+
+		for _, e := range report.Errors {
+			fmt.Fprintf(os.Stderr, "Warning: failed to query %s balances of account '%s': %s\n", e.Layer, e.Account, e.Error)
+		}
+		if len(report.Errors) > 0 {
+			cobra.CheckErr("some balances could not be queried and are missing from the totals")
+		}
+	},
+}
+
+func init() {
+	accountsPortfolioCmd.Flags().AddFlagSet(common.SelectorNPFlags)
+	accountsPortfolioCmd.Flags().AddFlagSet(common.HeightFlag)
+	accountsPortfolioCmd.Flags().AddFlagSet(common.TabularFormatFlag)
+
+	accountsCmd.AddCommand(accountsPortfolioCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+func TestPortfolioTotals(t *testing.T) {
+	require := require.New(t)
+
+	balance := func(symbol string, amount uint64, decimals uint8) *portfolioBalance {
+		return newPortfolioBalance("test", "", layerConsensus, portfolioKindGeneral, symbol, *quantity.NewFromUint64(amount), decimals)
+	}
+	totals := portfolioTotals([]*portfolioBalance{
+		balance("ROSE", 1_500_000_000, 9),
+		balance("USDT", 2_000_000, 6),
+		balance("ROSE", 250_000_000_000_000_000, 18),
+		balance("ROSE", 1, 9),
+	})
+	require.Len(totals, 2)
+	require.Equal("ROSE", totals[0].Denomination)
+	require.Equal("1.750000001", totals[0].Amount)
+	require.Equal("USDT", totals[1].Denomination)
+	require.Equal("2.0", totals[1].Amount)
+
+	require.Empty(portfolioTotals(nil))
+}
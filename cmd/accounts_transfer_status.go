@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/consensusaccounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+const (
+	transferKindDeposit  = "deposit"
+	transferKindWithdraw = "withdraw"
+)
+
+var transferStatusLookback uint64
+
+// transferResult is the outcome of a deposit or withdrawal as reported by the ParaTime.
+type transferResult struct {
+	Kind  string
+	From  types.Address
+	Nonce uint64
+	To    types.Address
+	Error *consensusaccounts.ConsensusError
+}
+
+// matchTransferEvent returns an event mapper matching the deposit or withdrawal (or either of them
+// if kind is empty) submitted by the given sender with the given nonce.
+func matchTransferEvent(kind string, from types.Address, nonce uint64) func(client.DecodedEvent) interface{} {
+	return func(ev client.DecodedEvent) interface{} {
+		ce, ok := ev.(*consensusaccounts.Event)
+		if !ok {
+			return nil
+		}
+		switch {
+		case ce.Deposit != nil && (kind == "" || kind == transferKindDeposit):
+			if ce.Deposit.From.Equal(from) && ce.Deposit.Nonce == nonce {
+				return &transferResult{
+					Kind:  transferKindDeposit,
+					From:  ce.Deposit.From,
+					Nonce: ce.Deposit.Nonce,
+					To:    ce.Deposit.To,
+					Error: ce.Deposit.Error,
+				}
+			}
+		case ce.Withdraw != nil && (kind == "" || kind == transferKindWithdraw):
+			if ce.Withdraw.From.Equal(from) && ce.Withdraw.Nonce == nonce {
+				return &transferResult{
+					Kind:  transferKindWithdraw,
+					From:  ce.Withdraw.From,
+					Nonce: ce.Withdraw.Nonce,
+					To:    ce.Withdraw.To,
+					Error: ce.Withdraw.Error,
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// transferStatusHint returns the command to check the result of a transfer later, on the same
+// network and ParaTime.
+func transferStatusHint(npa *common.NPASelection, from types.Address, nonce uint64) string {
+	hint := fmt.Sprintf("hela accounts transfer-status %s %d --network %s", from, nonce, npa.NetworkName)
+	if npa.ParaTimeName != "" {
+		hint += fmt.Sprintf(" --runtime %s", npa.ParaTimeName)
+	}
+	return hint
+}
+
+// waitForTransferResult waits for the result of the deposit or withdrawal with the given sender
+// and nonce and reports it.
+func waitForTransferResult(npa *common.NPASelection, waitCh <-chan interface{}, kind string, from types.Address, nonce uint64) {
+	fmt.Printf("Waiting for %s result...\n", kind)
+
+	ev := <-waitCh
+	if ev == nil {
+		cobra.CheckErr(fmt.Errorf("no %s result seen while waiting; to check it later, run:\n  %s", kind, transferStatusHint(npa, from, nonce)))
+	}
+	reportTransferResult(ev.(*transferResult))
+}
+
+// reportTransferResult prints the result of a deposit or withdrawal and fails if it was not
+// successful.
+func reportTransferResult(res *transferResult) {
+	if res.Error != nil {
+		cobra.CheckErr(fmt.Errorf("%s failed with error code %d from module %s",
+			res.Kind,
+			res.Error.Code,
+			res.Error.Module,
+		))
+	}
+	switch res.Kind {
+	case transferKindDeposit:
+		fmt.Printf("Deposit succeeded.\n")
+	case transferKindWithdraw:
+		fmt.Printf("Withdraw succeeded.\n")
+	}
+}
+
+// findTransferResult searches the ParaTime events for the result of the deposit or withdrawal with
+// the given sender and nonce, going back from the given round for at most lookback rounds (or to
+// the last retained round if lookback is zero). It returns the result and the round it was found
+// in, if any, and the lowest round searched.
+func findTransferResult(
+	ctx context.Context,
+	conn connection.Connection,
+	pt *config.ParaTime,
+	from types.Address,
+	nonce uint64,
+	round uint64,
+	lookback uint64,
+) (*transferResult, uint64, uint64, error) {
+	lastRetained, err := conn.Runtime(pt).GetLastRetainedBlock(ctx)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to query last retained runtime block: %w", err)
+	}
+	lowest := lastRetained.Header.Round
+	if lookback > 0 && round >= lowest+lookback {
+		lowest = round - lookback + 1
+	}
+
+	matchFn := matchTransferEvent("", from, nonce)
+	for r := round; r >= lowest; r-- {
+		evs, err := conn.Runtime(pt).ConsensusAccounts.GetEvents(ctx, r)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to query events at round %d: %w", r, err)
+		}
+		for _, ev := range evs {
+			if res := matchFn(ev); res != nil {
+				return res.(*transferResult), r, lowest, nil
+			}
+		}
+		if r == 0 {
+			break
+		}
+	}
+	return nil, 0, lowest, nil
+}
+
+var accountsTransferStatusCmd = &cobra.Command{
+	Use:   "transfer-status <from> <nonce>",
+	Short: "Show the result of a deposit or withdrawal",
+	Long: "Look up the result of the deposit or withdrawal submitted by the given account or address " +
+		"with the given nonce in recent ParaTime events. If it is not found, keep waiting for it " +
+		"unless --no-wait is given.",
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+
+		if npa.ParaTime == nil {
+			cobra.CheckErr("no runtime configured")
+		}
+
+		from, err := common.ResolveLocalAccountOrAddress(npa.Network, args[0])
+		cobra.CheckErr(err)
+		nonce, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			cobra.CheckErr(fmt.Errorf("malformed nonce: %w", err))
+		}
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		// Start watching before searching the history so that no event can be missed in between.
+		var waitCh <-chan interface{}
+		if common.ShouldWait() {
+			decoder := conn.Runtime(npa.ParaTime).ConsensusAccounts
+			waitCh = common.WaitForEvent(ctx, npa.ParaTime, conn, decoder, matchTransferEvent("", *from, nonce))
+		}
+
+		round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+		cobra.CheckErr(err)
+
+		res, resRound, lowest, err := findTransferResult(ctx, conn, npa.ParaTime, *from, nonce, round, transferStatusLookback)
+		cobra.CheckErr(err)
+		if res != nil {
+			fmt.Printf("Found %s result in round %d.\n", res.Kind, resRound)
+			reportTransferResult(res)
+			return
+		}
+
+		fmt.Printf("No deposit or withdrawal result found in rounds %d-%d.\n", lowest, round)
+		if waitCh == nil {
+			cobra.CheckErr("transfer result not found")
+		}
+
+		fmt.Printf("Waiting for result...\n")
+		ev := <-waitCh
+		if ev == nil {
+			cobra.CheckErr("no transfer result seen while waiting")
+		}
+		reportTransferResult(ev.(*transferResult))
+	},
+}
+
+func init() {
+	transferStatusFlags := flag.NewFlagSet("", flag.ContinueOnError)
+	transferStatusFlags.Uint64Var(&transferStatusLookback, "lookback", 1000, "number of rounds to search for the result (0 to search all retained rounds)")
+
+	accountsTransferStatusCmd.Flags().AddFlagSet(common.SelectorNPFlags)
+	accountsTransferStatusCmd.Flags().AddFlagSet(common.RoundFlag)
+	accountsTransferStatusCmd.Flags().AddFlagSet(common.WaitFlags)
+	accountsTransferStatusCmd.Flags().AddFlagSet(transferStatusFlags)
+
+	accountsCmd.AddCommand(accountsTransferStatusCmd)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/consensusaccounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+)
+
+func TestMatchTransferEvent(t *testing.T) {
+	require := require.New(t)
+
+	alice := types.NewAddressForModule("test", []byte("alice"))
+	bob := types.NewAddressForModule("test", []byte("bob"))
+	deposit := &consensusaccounts.Event{Deposit: &consensusaccounts.DepositEvent{From: alice, Nonce: 5, To: bob}}
+	withdraw := &consensusaccounts.Event{Withdraw: &consensusaccounts.WithdrawEvent{
+		From:  alice,
+		Nonce: 6,
+		Error: &consensusaccounts.ConsensusError{Module: "staking", Code: 3},
+	}}
+
+	res := matchTransferEvent(transferKindDeposit, alice, 5)(deposit)
+	require.NotNil(res)
+	require.Equal(transferKindDeposit, res.(*transferResult).Kind)
+	require.True(res.(*transferResult).To.Equal(bob))
+	require.Nil(res.(*transferResult).Error)
+
+	require.Nil(matchTransferEvent(transferKindDeposit, alice, 6)(deposit), "wrong nonce")
+	require.Nil(matchTransferEvent(transferKindDeposit, bob, 5)(deposit), "wrong sender")
+	require.Nil(matchTransferEvent(transferKindWithdraw, alice, 5)(deposit), "wrong kind")
+	require.Nil(matchTransferEvent("", alice, 5)("not an event"), "wrong event type")
+
+	res = matchTransferEvent("", alice, 6)(withdraw)
+	require.NotNil(res)
+	require.Equal(transferKindWithdraw, res.(*transferResult).Kind)
+	require.Equal(uint32(3), res.(*transferResult).Error.Code)
+}
+
+func TestTransferStatusHint(t *testing.T) {
+	require := require.New(t)
+
+	alice := types.NewAddressForModule("test", []byte("alice"))
+
+	npa := &common.NPASelection{NetworkName: "testnet", ParaTimeName: "sapphire"}
+	require.Equal("hela accounts transfer-status "+alice.String()+" 7 --network testnet --runtime sapphire", transferStatusHint(npa, alice, 7))
+
+	npa.ParaTimeName = ""
+	require.Equal("hela accounts transfer-status "+alice.String()+" 7 --network testnet", transferStatusHint(npa, alice, 7))
+}
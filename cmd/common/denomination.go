@@ -0,0 +1,76 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	selectedDenom string
+	txFeeDenom    string
+)
+
+// DenominationFlag is the flag for selecting the denomination of ParaTime amounts.
+var DenominationFlag *flag.FlagSet
+
+// ResolveDenomination resolves the given denomination name or symbol against the denominations
+// configured for the given ParaTime. An empty name selects the native denomination.
+func ResolveDenomination(pt *config.ParaTime, name string) (types.Denomination, error) {
+	if name == "" || name == config.NativeDenominationKey {
+		return types.NativeDenomination, nil
+	}
+
+	var known []string
+	for key, di := range pt.Denominations {
+		var denom types.Denomination
+		if key != config.NativeDenominationKey {
+			denom = types.Denomination(key)
+		}
+		if key == name || (di != nil && strings.EqualFold(di.Symbol, name)) {
+			return denom, nil
+		}
+
+		if di != nil && di.Symbol != "" {
+			known = append(known, di.Symbol)
+		} else {
+			known = append(known, key)
+		}
+	}
+	sort.Strings(known)
+	return "", fmt.Errorf("denomination '%s' is not configured for the ParaTime (available: %s)", name, strings.Join(known, ", "))
+}
+
+// GetDenomination returns the denomination of ParaTime amounts selected via --denom.
+func GetDenomination(pt *config.ParaTime) (types.Denomination, error) {
+	return ResolveDenomination(pt, selectedDenom)
+}
+
+// CheckConsensusDenomination checks that the denomination selected via --denom, if any, is the
+// denomination of the consensus layer of the given network.
+func CheckConsensusDenomination(net *config.Network) error {
+	if selectedDenom != "" && !strings.EqualFold(selectedDenom, net.Denomination.Symbol) {
+		return fmt.Errorf("denomination '%s' is not available on the consensus layer", selectedDenom)
+	}
+	return nil
+}
+
+// GetFeeDenomination returns the denomination for paying ParaTime gas fees selected via
+// --fee-denom.
+func GetFeeDenomination(pt *config.ParaTime) (types.Denomination, error) {
+	denom, err := ResolveDenomination(pt, txFeeDenom)
+	if err != nil {
+		return "", fmt.Errorf("bad fee denomination: %w", err)
+	}
+	return denom, nil
+}
+
+func init() {
+	DenominationFlag = flag.NewFlagSet("", flag.ContinueOnError)
+	DenominationFlag.StringVar(&selectedDenom, "denom", "", "denomination of the amount (default: native)")
+}
@@ -0,0 +1,251 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusTx "github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+const (
+	gasPriceStrategyMin = "min"
+
+	// gasPriceSampleBlocks is the number of recent blocks whose fees are sampled for the
+	// percentile gas price strategy.
+	gasPriceSampleBlocks = 20
+)
+
+var (
+	txMaxFee           string
+	txGasPriceStrategy string
+)
+
+// gasPriceStrategy is a parsed gas price strategy.
+type gasPriceStrategy struct {
+	// multiplier is the factor the minimum gas price is multiplied with.
+	multiplier *big.Rat
+	// percentile is the percentile of recently paid gas prices to use, if non-zero.
+	percentile uint64
+}
+
+// parseGasPriceStrategy parses a gas price strategy of the form "min", "min*<factor>" or
+// "p<percentile>".
+func parseGasPriceStrategy(raw string) (*gasPriceStrategy, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	switch {
+	case raw == gasPriceStrategyMin:
+		return &gasPriceStrategy{multiplier: big.NewRat(1, 1)}, nil
+	case strings.HasPrefix(raw, gasPriceStrategyMin+"*"):
+		multiplier, ok := new(big.Rat).SetString(strings.TrimPrefix(raw, gasPriceStrategyMin+"*"))
+		if !ok || multiplier.Sign() <= 0 {
+			return nil, fmt.Errorf("malformed gas price multiplier in '%s'", raw)
+		}
+		return &gasPriceStrategy{multiplier: multiplier}, nil
+	case strings.HasPrefix(raw, "p"):
+		percentile, err := strconv.ParseUint(strings.TrimPrefix(raw, "p"), 10, 64)
+		if err != nil || percentile == 0 || percentile > 100 {
+			return nil, fmt.Errorf("malformed gas price percentile in '%s' (must be between p1 and p100)", raw)
+		}
+		return &gasPriceStrategy{percentile: percentile}, nil
+	default:
+		return nil, fmt.Errorf("unknown gas price strategy '%s' (must be min, min*<factor> or p<percentile>)", raw)
+	}
+}
+
+// resolve returns the gas price selected by the strategy, given the minimum gas price and a
+// function returning the gas prices paid in recent blocks. The percentile strategy never returns
+// less than the minimum gas price.
+func (s *gasPriceStrategy) resolve(minPrice *big.Int, samples func() ([]*big.Int, error)) (*big.Int, error) {
+	if s.percentile == 0 {
+		// Round up so that the result is never below the requested multiple.
+		v := new(big.Int).Mul(minPrice, s.multiplier.Num())
+		v.Add(v, new(big.Int).Sub(s.multiplier.Denom(), big.NewInt(1)))
+		return v.Quo(v, s.multiplier.Denom()), nil
+	}
+
+	prices, err := samples()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample recent gas prices: %w", err)
+	}
+	price := gasPricePercentile(prices, s.percentile)
+	if price == nil || price.Cmp(minPrice) < 0 {
+		return new(big.Int).Set(minPrice), nil
+	}
+	return price, nil
+}
+
+// gasPricePercentile returns the given percentile of the given gas prices, or nil if there are
+// none.
+func gasPricePercentile(prices []*big.Int, percentile uint64) *big.Int {
+	if len(prices) == 0 {
+		return nil
+	}
+	sorted := append([]*big.Int{}, prices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+
+	idx := (uint64(len(sorted))*percentile + 99) / 100
+	if idx > 0 {
+		idx--
+	}
+	return new(big.Int).Set(sorted[idx])
+}
+
+// getGasPriceStrategy returns the gas price strategy given via --gas-price-strategy.
+func getGasPriceStrategy() (*gasPriceStrategy, error) {
+	strategy, err := parseGasPriceStrategy(txGasPriceStrategy)
+	if err != nil {
+		return nil, err
+	}
+	explicit := strings.ToLower(strings.TrimSpace(txGasPriceStrategy)) != gasPriceStrategyMin
+	if txGasPrice != "" && explicit {
+		return nil, fmt.Errorf("--gas-price and --gas-price-strategy are mutually exclusive")
+	}
+	if txOffline && explicit {
+		return nil, fmt.Errorf("gas price strategy '%s' is not available in offline mode", txGasPriceStrategy)
+	}
+	return strategy, nil
+}
+
+// consensusGasPrices returns the gas prices paid by transactions in recent consensus blocks.
+func consensusGasPrices(ctx context.Context, conn connection.Connection) ([]*big.Int, error) {
+	blk, err := conn.Consensus().GetBlock(ctx, consensus.HeightLatest)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []*big.Int
+	for height := blk.Height; height > 0 && height > blk.Height-gasPriceSampleBlocks; height-- {
+		txs, err := conn.Consensus().GetTransactions(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range txs {
+			var sigTx consensusTx.SignedTransaction
+			if err = cbor.Unmarshal(raw, &sigTx); err != nil {
+				continue
+			}
+			var tx consensusTx.Transaction
+			if err = cbor.Unmarshal(sigTx.Blob, &tx); err != nil || tx.Fee == nil || tx.Fee.Gas == 0 {
+				continue
+			}
+			price := tx.Fee.Amount.ToBigInt()
+			prices = append(prices, price.Quo(price, new(big.Int).SetUint64(uint64(tx.Fee.Gas))))
+		}
+	}
+	return prices, nil
+}
+
+// paraTimeGasPrices returns the gas prices paid in the given denomination by transactions in
+// recent ParaTime blocks.
+func paraTimeGasPrices(ctx context.Context, npa *NPASelection, conn connection.Connection, denom types.Denomination) ([]*big.Int, error) {
+	rt := conn.Runtime(npa.ParaTime)
+	blk, err := rt.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []*big.Int
+	for round := blk.Header.Round; round+gasPriceSampleBlocks > blk.Header.Round; round-- {
+		txs, err := rt.GetTransactions(ctx, round)
+		if err != nil {
+			return nil, err
+		}
+		for _, utx := range txs {
+			var tx types.Transaction
+			if err = cbor.Unmarshal(utx.Body, &tx); err != nil {
+				continue
+			}
+			fee := tx.AuthInfo.Fee
+			if fee.Gas == 0 || fee.Amount.Denomination != denom {
+				continue
+			}
+			price := fee.Amount.Amount.ToBigInt()
+			prices = append(prices, price.Quo(price, new(big.Int).SetUint64(fee.Gas)))
+		}
+		if round == 0 {
+			break
+		}
+	}
+	return prices, nil
+}
+
+// checkConsensusFee checks the given consensus transaction fee against the limit given via
+// --max-fee and the limit configured for the selected network.
+func checkConsensusFee(npa *NPASelection, fee *quantity.Quantity) error {
+	if txMaxFee != "" {
+		limit, err := helpers.ParseConsensusDenomination(npa.Network, txMaxFee)
+		if err != nil {
+			return fmt.Errorf("bad maximum fee: %w", err)
+		}
+		if fee.Cmp(limit) > 0 {
+			return fmt.Errorf("fee %s exceeds the maximum fee %s", helpers.FormatConsensusDenomination(npa.Network, *fee), helpers.FormatConsensusDenomination(npa.Network, *limit))
+		}
+	}
+
+	if cfgLimit := cliConfig.Global().FeeLimits.All[npa.NetworkName]; cfgLimit != nil && cfgLimit.Consensus != "" {
+		limit, err := helpers.ParseConsensusDenomination(npa.Network, cfgLimit.Consensus)
+		if err != nil {
+			return fmt.Errorf("bad configured fee limit: %w", err)
+		}
+		if fee.Cmp(limit) > 0 {
+			return fmt.Errorf("fee %s exceeds the limit %s configured for network %s", helpers.FormatConsensusDenomination(npa.Network, *fee), helpers.FormatConsensusDenomination(npa.Network, *limit), npa.NetworkName)
+		}
+	}
+	return nil
+}
+
+// checkParaTimeFee checks the given ParaTime transaction fee against the limit given via
+// --max-fee and the limit configured for the selected network and the fee denomination.
+func checkParaTimeFee(npa *NPASelection, fee *types.BaseUnits) error {
+	exceeds := func(raw string) (*types.BaseUnits, error) {
+		limit, err := helpers.ParseParaTimeDenomination(npa.ParaTime, raw, fee.Denomination)
+		if err != nil {
+			return nil, err
+		}
+		if fee.Amount.Cmp(&limit.Amount) > 0 {
+			return limit, nil
+		}
+		return nil, nil
+	}
+
+	if txMaxFee != "" {
+		limit, err := exceeds(txMaxFee)
+		if err != nil {
+			return fmt.Errorf("bad maximum fee: %w", err)
+		}
+		if limit != nil {
+			return fmt.Errorf("fee %s exceeds the maximum fee %s", helpers.FormatParaTimeDenomination(npa.ParaTime, *fee), helpers.FormatParaTimeDenomination(npa.ParaTime, *limit))
+		}
+	}
+
+	cfgLimit := cliConfig.Global().FeeLimits.All[npa.NetworkName]
+	if cfgLimit == nil {
+		return nil
+	}
+	for denomName, raw := range cfgLimit.ParaTime {
+		if denom, err := ResolveDenomination(npa.ParaTime, denomName); err != nil || denom != fee.Denomination {
+			continue
+		}
+		limit, err := exceeds(raw)
+		if err != nil {
+			return fmt.Errorf("bad configured fee limit: %w", err)
+		}
+		if limit != nil {
+			return fmt.Errorf("fee %s exceeds the limit %s configured for network %s", helpers.FormatParaTimeDenomination(npa.ParaTime, *fee), helpers.FormatParaTimeDenomination(npa.ParaTime, *limit), npa.NetworkName)
+		}
+	}
+	return nil
+}
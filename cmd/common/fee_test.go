@@ -0,0 +1,55 @@
+package common
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasPriceStrategy(t *testing.T) {
+	require := require.New(t)
+
+	noSamples := func() ([]*big.Int, error) {
+		require.Fail("samples should not be queried")
+		return nil, nil
+	}
+	samples := func() ([]*big.Int, error) {
+		return []*big.Int{big.NewInt(40), big.NewInt(10), big.NewInt(30), big.NewInt(20)}, nil
+	}
+
+	s, err := parseGasPriceStrategy("min")
+	require.NoError(err)
+	price, err := s.resolve(big.NewInt(100), noSamples)
+	require.NoError(err)
+	require.EqualValues(100, price.Int64())
+
+	s, err = parseGasPriceStrategy("MIN*1.5")
+	require.NoError(err)
+	price, err = s.resolve(big.NewInt(7), noSamples)
+	require.NoError(err)
+	require.EqualValues(11, price.Int64(), "multiples are rounded up")
+
+	s, err = parseGasPriceStrategy("p50")
+	require.NoError(err)
+	price, err = s.resolve(big.NewInt(5), samples)
+	require.NoError(err)
+	require.EqualValues(20, price.Int64())
+	price, err = s.resolve(big.NewInt(25), samples)
+	require.NoError(err)
+	require.EqualValues(25, price.Int64(), "never below the minimum gas price")
+	price, err = s.resolve(big.NewInt(5), func() ([]*big.Int, error) { return nil, nil })
+	require.NoError(err)
+	require.EqualValues(5, price.Int64(), "minimum gas price without samples")
+
+	s, err = parseGasPriceStrategy("p100")
+	require.NoError(err)
+	price, err = s.resolve(big.NewInt(0), samples)
+	require.NoError(err)
+	require.EqualValues(40, price.Int64())
+
+	for _, raw := range []string{"", "max", "min*", "min*0", "min*-1", "p0", "p101", "pX"} {
+		_, err = parseGasPriceStrategy(raw)
+		require.Error(err, raw)
+	}
+}
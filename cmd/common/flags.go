@@ -51,6 +51,10 @@ func init() {
 	HeightFlag = flag.NewFlagSet("", flag.ContinueOnError)
 	HeightFlag.Int64Var(&selectedHeight, "height", consensus.HeightLatest, "explicitly set block height to use")
 
+	RoundFlag = flag.NewFlagSet("", flag.ContinueOnError)
+	RoundFlag.Uint64Var(&selectedRound, "round", 0, "explicitly set runtime round to use")
+	RoundFlag.AddFlagSet(HeightFlag)
+
 	ForceFlag = flag.NewFlagSet("", flag.ContinueOnError)
 	ForceFlag.BoolVarP(&force, "force", "f", false, "treat safety check errors as warnings")
 }
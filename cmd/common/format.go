@@ -0,0 +1,76 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// FormatTable is the human-readable table output format.
+	FormatTable = "table"
+	// FormatJSON is the JSON output format.
+	FormatJSON = "json"
+	// FormatYAML is the YAML output format.
+	FormatYAML = "yaml"
+	// FormatCSV is the CSV output format.
+	FormatCSV = "csv"
+)
+
+var outputFormat string
+
+var (
+	// FormatFlag is the flag for selecting the output format.
+	FormatFlag *flag.FlagSet
+	// TabularFormatFlag is the flag for selecting the output format of commands whose output can
+	// also be exported as CSV.
+	TabularFormatFlag *flag.FlagSet
+)
+
+// GetOutputFormat returns the user-selected output format.
+func GetOutputFormat() string {
+	switch outputFormat {
+	case FormatTable, FormatJSON, FormatYAML:
+		return outputFormat
+	default:
+		cobra.CheckErr(fmt.Errorf("unsupported output format '%s'", outputFormat))
+	}
+	return ""
+}
+
+// GetTabularOutputFormat returns the user-selected output format including CSV.
+func GetTabularOutputFormat() string {
+	if outputFormat == FormatCSV {
+		return outputFormat
+	}
+	return GetOutputFormat()
+}
+
+// PrintStructured prints v in the given structured output format (JSON or YAML).
+//
+// YAML output is derived from the JSON encoding so that both formats share the same schema.
+func PrintStructured(format string, v interface{}) {
+	formatted, err := PrettyJSONMarshal(v)
+	cobra.CheckErr(err)
+
+	if format == FormatYAML {
+		var obj interface{}
+		err = yaml.Unmarshal(formatted, &obj)
+		cobra.CheckErr(err)
+		formatted, err = yaml.Marshal(obj)
+		cobra.CheckErr(err)
+		fmt.Print(string(formatted))
+		return
+	}
+	fmt.Println(string(formatted))
+}
+
+func init() {
+	FormatFlag = flag.NewFlagSet("", flag.ContinueOnError)
+	FormatFlag.StringVar(&outputFormat, "format", FormatTable, "output format [table, json, yaml]")
+
+	TabularFormatFlag = flag.NewFlagSet("", flag.ContinueOnError)
+	TabularFormatFlag.StringVar(&outputFormat, "format", FormatTable, "output format [table, json, yaml, csv]")
+}
@@ -8,19 +8,23 @@ import (
 
 // CheckForceErr treats error as warning, if --force is provided.
 func CheckForceErr(err interface{}) {
+	cobra.CheckErr(ForceErr(err))
+}
+
+// ForceErr treats error as warning, if --force is provided. Otherwise it returns the error with a
+// hint to use --force.
+func ForceErr(err interface{}) error {
 	// No error.
 	if err == nil {
-		return
+		return nil
 	}
 
 	// --force is provided.
 	if IsForce() {
 		fmt.Printf("Warning: %s\nProceeding by force as requested\n", err)
-		return
+		return nil
 	}
 
-	// Print error with --force hint and quit.
-	errMsg := fmt.Sprintf("%s", err)
-	errMsg += "\nUse --force to ignore this check"
-	cobra.CheckErr(errMsg)
+	// Return error with --force hint.
+	return fmt.Errorf("%s\nUse --force to ignore this check", err)
 }
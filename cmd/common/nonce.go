@@ -0,0 +1,240 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+const (
+	nonceFileName = "nonces.json"
+
+	// nonceStaleAfter is the time after which nonces handed out for pending transactions that
+	// never made it on chain are discarded in favor of the on-chain nonce.
+	nonceStaleAfter = 10 * time.Minute
+
+	nonceLockTimeout = 5 * time.Second
+	nonceLockStale   = 30 * time.Second
+
+	// NonceLayerConsensus is the nonce tracker layer name of the consensus layer.
+	NonceLayerConsensus = "consensus"
+)
+
+// NonceEntry is the locally tracked nonce state of an account.
+type NonceEntry struct {
+	// Next is the next nonce to hand out.
+	Next uint64 `json:"next"`
+	// Updated is the time the last nonce was handed out.
+	Updated time.Time `json:"updated"`
+}
+
+// Reconcile returns the next nonce to use given the on-chain nonce. Locally handed out nonces are
+// only trusted while they are ahead of the chain and not stale.
+func (e *NonceEntry) Reconcile(chainNonce uint64, now time.Time) uint64 {
+	if e == nil || e.Next <= chainNonce || now.Sub(e.Updated) > nonceStaleAfter {
+		return chainNonce
+	}
+	return e.Next
+}
+
+// NonceLayer returns the nonce tracker layer name of the selected ParaTime or the consensus layer
+// if no ParaTime is selected.
+func NonceLayer(npa *NPASelection) string {
+	if npa.ParaTime == nil {
+		return NonceLayerConsensus
+	}
+	return "paratime/" + npa.ParaTimeName
+}
+
+func nonceKey(npa *NPASelection, layer string, addr types.Address) string {
+	return fmt.Sprintf("%s/%s/%s", npa.NetworkName, layer, addr)
+}
+
+func nonceFilePath() string {
+	return filepath.Join(cliConfig.Directory(), nonceFileName)
+}
+
+// lockNonceFile acquires the lock of the nonce tracker file and returns a function releasing it.
+func lockNonceFile() (func(), error) {
+	lockPath := nonceFilePath() + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o700); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(nonceLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		// Break locks left behind by crashed processes.
+		if fi, serr := os.Stat(lockPath); serr == nil && time.Since(fi.ModTime()) > nonceLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for nonce tracker lock %s", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// updateNonces runs the given function on the locked nonce tracker state and saves the result.
+func updateNonces(fn func(entries map[string]*NonceEntry) error) error {
+	unlock, err := lockNonceFile()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries := make(map[string]*NonceEntry)
+	raw, err := ioutil.ReadFile(nonceFilePath())
+	switch {
+	case err == nil:
+		if err = json.Unmarshal(raw, &entries); err != nil {
+			return fmt.Errorf("malformed nonce tracker file %s: %w", nonceFilePath(), err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return err
+	}
+
+	if err = fn(entries); err != nil {
+		return err
+	}
+
+	raw, err = json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := nonceFilePath() + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, nonceFilePath())
+}
+
+// GetTrackedNonce returns the locally tracked nonce state of the given account in the given layer
+// of the selected network, or nil if there is none.
+func GetTrackedNonce(npa *NPASelection, layer string, addr types.Address) (*NonceEntry, error) {
+	var entry *NonceEntry
+	err := updateNonces(func(entries map[string]*NonceEntry) error {
+		entry = entries[nonceKey(npa, layer, addr)]
+		return nil
+	})
+	return entry, err
+}
+
+// ResetTrackedNonce removes the locally tracked nonce state of the given account in the given
+// layer of the selected network so that the on-chain nonce is used again.
+func ResetTrackedNonce(npa *NPASelection, layer string, addr types.Address) error {
+	return updateNonces(func(entries map[string]*NonceEntry) error {
+		delete(entries, nonceKey(npa, layer, addr))
+		return nil
+	})
+}
+
+// NextNonce returns the nonce to use for the next transaction of the given account in the given
+// layer. It queries the on-chain nonce and takes transactions that are still pending into account.
+func NextNonce(ctx context.Context, npa *NPASelection, conn connection.Connection, layer string, addr types.Address) (uint64, error) {
+	var (
+		chainNonce uint64
+		err        error
+	)
+	switch layer {
+	case NonceLayerConsensus:
+		chainNonce, err = conn.Consensus().GetSignerNonce(ctx, &consensus.GetSignerNonceRequest{
+			AccountAddress: addr.ConsensusAddress(),
+			Height:         consensus.HeightLatest,
+		})
+	default:
+		chainNonce, err = conn.Runtime(npa.ParaTime).Accounts.Nonce(ctx, client.RoundLatest, addr)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query nonce: %w", err)
+	}
+
+	entry, err := GetTrackedNonce(npa, layer, addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load nonce tracker, using on-chain nonce: %s\n", err)
+		return chainNonce, nil
+	}
+
+	nonce := entry.Reconcile(chainNonce, time.Now())
+	if nonce != chainNonce {
+		fmt.Fprintf(os.Stderr, "Using nonce %d (%d pending transactions ahead of on-chain nonce %d).\n", nonce, nonce-chainNonce, chainNonce)
+	}
+	return nonce, nil
+}
+
+// RecordTrackedNonce records that the given nonce has been used by a transaction of the given
+// account which has been accepted by the network.
+func RecordTrackedNonce(npa *NPASelection, layer string, addr types.Address, nonce uint64) {
+	err := updateNonces(func(entries map[string]*NonceEntry) error {
+		key := nonceKey(npa, layer, addr)
+		if entry := entries[key]; entry != nil && entry.Next > nonce+1 {
+			entry.Updated = time.Now()
+			return nil
+		}
+		entries[key] = &NonceEntry{
+			Next:    nonce + 1,
+			Updated: time.Now(),
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update nonce tracker: %s\n", err)
+	}
+}
+
+// pendingNonce is a tracked nonce of a signed transaction that has not been submitted yet.
+type pendingNonce struct {
+	npa   *NPASelection
+	layer string
+	addr  types.Address
+	nonce uint64
+}
+
+var (
+	pendingNoncesLock sync.Mutex
+	pendingNonces     = make(map[interface{}]*pendingNonce)
+)
+
+// setPendingNonce remembers the tracked nonce of the given signed transaction, so that it can be
+// recorded once the transaction has been accepted by the network.
+func setPendingNonce(sigTx interface{}, npa *NPASelection, layer string, addr types.Address, nonce uint64) {
+	pendingNoncesLock.Lock()
+	defer pendingNoncesLock.Unlock()
+	pendingNonces[sigTx] = &pendingNonce{npa: npa, layer: layer, addr: addr, nonce: nonce}
+}
+
+// commitPendingNonce records the tracked nonce of the given signed transaction, if any, after the
+// transaction has been accepted by the network.
+func commitPendingNonce(sigTx interface{}) {
+	pendingNoncesLock.Lock()
+	p := pendingNonces[sigTx]
+	delete(pendingNonces, sigTx)
+	pendingNoncesLock.Unlock()
+
+	if p != nil {
+		RecordTrackedNonce(p.npa, p.layer, p.addr, p.nonce)
+	}
+}
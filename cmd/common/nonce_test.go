@@ -0,0 +1,21 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceEntryReconcile(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	var missing *NonceEntry
+	require.EqualValues(5, missing.Reconcile(5, now), "no tracked nonce")
+
+	entry := &NonceEntry{Next: 8, Updated: now.Add(-time.Minute)}
+	require.EqualValues(8, entry.Reconcile(5, now), "pending transactions")
+	require.EqualValues(9, entry.Reconcile(9, now), "chain is ahead")
+	require.EqualValues(5, entry.Reconcile(5, now.Add(nonceStaleAfter)), "stale pending transactions")
+}
@@ -0,0 +1,140 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+)
+
+var selectedRound uint64
+
+// RoundFlag is the flag for specifying the runtime round. It also includes the block height flag
+// which can be used to select the runtime round at the given consensus height instead.
+var RoundFlag *flag.FlagSet
+
+// GetRound returns the user-selected runtime round, or client.RoundLatest if none was selected.
+func GetRound() uint64 {
+	if !RoundFlag.Lookup("round").Changed {
+		return client.RoundLatest
+	}
+	return selectedRound
+}
+
+// GetLatestRound returns the current latest runtime round.
+func GetLatestRound(ctx context.Context, conn connection.Connection, pt *config.ParaTime) (uint64, error) {
+	blk, err := conn.Runtime(pt).GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query latest runtime block: %w", err)
+	}
+	return blk.Header.Round, nil
+}
+
+// GetActualRound returns the runtime round selected either explicitly via --round or via the
+// consensus block height given by --height, or the current latest round otherwise. The round used
+// is reported on standard error so that it does not interfere with structured output.
+//
+// Note: Public gRPC endpoints do not allow querying historical rounds.
+func GetActualRound(ctx context.Context, conn connection.Connection, pt *config.ParaTime) (uint64, error) {
+	round, err := resolveRound(ctx, conn, pt)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Fprintf(os.Stderr, "Using runtime round %d.\n", round)
+	return round, nil
+}
+
+// GetActualRoundAndHeight returns the runtime round selected like GetActualRound together with a
+// consensus block height at which that round is the latest runtime round, so that runtime and
+// consensus state can be queried from the same snapshot.
+func GetActualRoundAndHeight(ctx context.Context, conn connection.Connection, pt *config.ParaTime) (uint64, int64, error) {
+	round, height := GetRound(), GetHeight()
+	switch {
+	case round != client.RoundLatest && height != consensus.HeightLatest:
+		return 0, 0, fmt.Errorf("--round and --height are mutually exclusive")
+	case round != client.RoundLatest:
+		var err error
+		if height, err = findRoundHeight(ctx, conn, pt, round); err != nil {
+			return 0, 0, err
+		}
+	default:
+		var err error
+		if height == consensus.HeightLatest {
+			if height, err = GetActualHeight(ctx, conn.Consensus()); err != nil {
+				return 0, 0, err
+			}
+		}
+		if round, err = RuntimeRoundAt(ctx, conn, pt, height); err != nil {
+			return 0, 0, err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Using runtime round %d at consensus height %d.\n", round, height)
+	return round, height, nil
+}
+
+func resolveRound(ctx context.Context, conn connection.Connection, pt *config.ParaTime) (uint64, error) {
+	round, height := GetRound(), GetHeight()
+	switch {
+	case round != client.RoundLatest && height != consensus.HeightLatest:
+		return 0, fmt.Errorf("--round and --height are mutually exclusive")
+	case round != client.RoundLatest:
+		return round, nil
+	case height != consensus.HeightLatest:
+		return RuntimeRoundAt(ctx, conn, pt, height)
+	default:
+		return GetLatestRound(ctx, conn, pt)
+	}
+}
+
+// RuntimeRoundAt returns the latest runtime round at the given consensus height.
+func RuntimeRoundAt(ctx context.Context, conn connection.Connection, pt *config.ParaTime, height int64) (uint64, error) {
+	blk, err := conn.Consensus().RootHash().GetLatestBlock(
+		ctx,
+		&roothash.RuntimeRequest{
+			RuntimeID: pt.Namespace(),
+			Height:    height,
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query runtime block at height %d: %w", height, err)
+	}
+	return blk.Header.Round, nil
+}
+
+// findRoundHeight returns the first retained consensus height at which the given runtime round is
+// the latest one.
+func findRoundHeight(ctx context.Context, conn connection.Connection, pt *config.ParaTime, round uint64) (int64, error) {
+	status, err := conn.Consensus().GetStatus(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query consensus status: %w", err)
+	}
+
+	lo, hi := status.LastRetainedHeight, status.LatestHeight
+	latest, err := RuntimeRoundAt(ctx, conn, pt, hi)
+	if err != nil {
+		return 0, err
+	}
+	if latest < round {
+		return 0, fmt.Errorf("runtime round %d is not available yet (latest round is %d)", round, latest)
+	}
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		midRound, err := RuntimeRoundAt(ctx, conn, pt, mid)
+		if err != nil {
+			return 0, err
+		}
+		if midRound >= round {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+func TestGetRound(t *testing.T) {
+	require := require.New(t)
+
+	roundFlag := RoundFlag.Lookup("round")
+	defer func() {
+		selectedRound = 0
+		roundFlag.Changed = false
+	}()
+
+	require.EqualValues(client.RoundLatest, GetRound())
+
+	require.NoError(RoundFlag.Parse([]string{"--round", "0"}))
+	require.EqualValues(0, GetRound(), "explicit round 0 should not be treated as latest")
+
+	require.NoError(RoundFlag.Parse([]string{"--round", "42"}))
+	require.EqualValues(42, GetRound())
+}
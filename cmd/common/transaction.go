@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -57,6 +58,29 @@ func GetTransactionConfig() *TransactionConfig {
 	}
 }
 
+// SignOption is an option of SignConsensusTransaction and SignParaTimeTransaction.
+type SignOption func(*signOptions)
+
+type signOptions struct {
+	confirm bool
+}
+
+func newSignOptions(opts []SignOption) *signOptions {
+	o := &signOptions{confirm: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithoutConfirmation signs the transaction without printing it and asking for confirmation, e.g.
+// in unattended services. Callers are responsible for obtaining consent beforehand.
+func WithoutConfirmation() SignOption {
+	return func(o *signOptions) {
+		o.confirm = false
+	}
+}
+
 // SignConsensusTransaction signs a consensus transaction.
 func SignConsensusTransaction(
 	ctx context.Context,
@@ -64,88 +88,33 @@ func SignConsensusTransaction(
 	wallet wallet.Account,
 	conn connection.Connection,
 	tx *consensusTx.Transaction,
+	opts ...SignOption,
 ) (*consensusTx.SignedTransaction, error) {
-	// Require consensus signer.
-	signer := wallet.ConsensusSigner()
-	if signer == nil {
-		return nil, fmt.Errorf("account does not support signing consensus transactions")
-	}
-
-	// Default to passed values and do online estimation when possible.
-	tx.Nonce = txNonce
-	if tx.Fee == nil {
-		tx.Fee = &consensusTx.Fee{}
-	}
-	tx.Fee.Gas = consensusTx.Gas(txGasLimit)
-
-	gasPrice := quantity.NewQuantity()
-	if txGasPrice != "" {
-		var err error
-		gasPrice, err = helpers.ParseConsensusDenomination(npa.Network, txGasPrice)
-		if err != nil {
-			return nil, fmt.Errorf("bad gas price: %w", err)
-		}
-	}
-
-	if !txOffline { //nolint: nestif
-		// Query nonce if not specified.
-		if tx.Nonce == invalidNonce {
-			nonce, err := conn.Consensus().GetSignerNonce(ctx, &consensus.GetSignerNonceRequest{
-				AccountAddress: wallet.Address().ConsensusAddress(),
-				Height:         consensus.HeightLatest,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to query nonce: %w", err)
-			}
-			tx.Nonce = nonce
-		}
-
-		// Gas estimation if not specified.
-		if tx.Fee.Gas == invalidGasLimit {
-			gas, err := conn.Consensus().EstimateGas(ctx, &consensus.EstimateGasRequest{
-				Signer:      signer.Public(),
-				Transaction: tx,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to estimate gas: %w", err)
-			}
-			tx.Fee.Gas = gas
-		}
-	}
-
-	// If we are using offline mode and either nonce or gas limit is not specified, abort.
-	if tx.Nonce == invalidNonce || tx.Fee.Gas == invalidGasLimit {
-		return nil, fmt.Errorf("nonce and/or gas limit must be specified in offline mode")
-	}
-
-	// Compute fee amount based on gas price.
-	if err := gasPrice.Mul(quantity.NewFromUint64(uint64(tx.Fee.Gas))); err != nil {
-		return nil, err
-	}
-	tx.Fee.Amount = *gasPrice
-
-	PrintTransactionBeforeSigning(npa, tx)
-
-	// Sign the transaction.
-	// NOTE: We build our own domain separation context here as we need to support multiple chain
-	//       contexts at the same time. Would be great if chainContextSeparator was exposed in core.
-	sigCtx := coreSignature.Context([]byte(fmt.Sprintf("%s for chain %s", consensusTx.SignatureContext, npa.Network.ChainContext)))
-	signed, err := coreSignature.SignSigned(signer, sigCtx, tx)
-	if err != nil {
-		return nil, err
-	}
+	return signConsensusTransaction(ctx, npa, wallet, conn, tx, txNonce, newSignOptions(opts).confirm)
+}
 
-	return &consensusTx.SignedTransaction{Signed: *signed}, nil
+// SignConsensusTransactionWithNonce signs a consensus transaction using the given nonce without
+// printing the transaction and asking for confirmation. Callers are responsible for obtaining
+// confirmation from the user beforehand.
+func SignConsensusTransactionWithNonce(
+	ctx context.Context,
+	npa *NPASelection,
+	wallet wallet.Account,
+	conn connection.Connection,
+	tx *consensusTx.Transaction,
+	nonce uint64,
+) (*consensusTx.SignedTransaction, error) {
+	return signConsensusTransaction(ctx, npa, wallet, conn, tx, nonce, false)
 }
 
-// MZ
-// SignConsensusTransactionFaucet signs a consensus transaction. Temporarily used for faucet.
-func SignConsensusTransactionFaucet(
+func signConsensusTransaction(
 	ctx context.Context,
 	npa *NPASelection,
 	wallet wallet.Account,
 	conn connection.Connection,
 	tx *consensusTx.Transaction,
+	nonce uint64,
+	confirm bool,
 ) (*consensusTx.SignedTransaction, error) {
 	// Require consensus signer.
 	signer := wallet.ConsensusSigner()
@@ -154,7 +123,7 @@ func SignConsensusTransactionFaucet(
 	}
 
 	// Default to passed values and do online estimation when possible.
-	tx.Nonce = txNonce
+	tx.Nonce = nonce
 	if tx.Fee == nil {
 		tx.Fee = &consensusTx.Fee{}
 	}
@@ -168,18 +137,19 @@ func SignConsensusTransactionFaucet(
 			return nil, fmt.Errorf("bad gas price: %w", err)
 		}
 	}
+	strategy, err := getGasPriceStrategy()
+	if err != nil {
+		return nil, err
+	}
 
+	var trackNonce bool
 	if !txOffline { //nolint: nestif
-		// Query nonce if not specified.
+		// Query nonce if not specified, taking pending transactions into account.
 		if tx.Nonce == invalidNonce {
-			nonce, err := conn.Consensus().GetSignerNonce(ctx, &consensus.GetSignerNonceRequest{
-				AccountAddress: wallet.Address().ConsensusAddress(),
-				Height:         consensus.HeightLatest,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to query nonce: %w", err)
+			if tx.Nonce, err = NextNonce(ctx, npa, conn, NonceLayerConsensus, wallet.Address()); err != nil {
+				return nil, err
 			}
-			tx.Nonce = nonce
+			trackNonce = true
 		}
 
 		// Gas estimation if not specified.
@@ -193,6 +163,20 @@ func SignConsensusTransactionFaucet(
 			}
 			tx.Fee.Gas = gas
 		}
+
+		// Gas price determination if not specified. The consensus layer has no minimum gas price
+		// that could be queried, so only the percentile strategy affects it.
+		if txGasPrice == "" {
+			price, err := strategy.resolve(new(big.Int), func() ([]*big.Int, error) {
+				return consensusGasPrices(ctx, conn)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err = gasPrice.FromBigInt(price); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// If we are using offline mode and either nonce or gas limit is not specified, abort.
@@ -206,7 +190,13 @@ func SignConsensusTransactionFaucet(
 	}
 	tx.Fee.Amount = *gasPrice
 
-	// PrintTransactionBeforeSigning(npa, tx)
+	if err = ForceErr(checkConsensusFee(npa, &tx.Fee.Amount)); err != nil {
+		return nil, err
+	}
+
+	if confirm {
+		PrintTransactionBeforeSigning(npa, tx)
+	}
 
 	// Sign the transaction.
 	// NOTE: We build our own domain separation context here as we need to support multiple chain
@@ -216,8 +206,12 @@ func SignConsensusTransactionFaucet(
 	if err != nil {
 		return nil, err
 	}
+	sigTx := &consensusTx.SignedTransaction{Signed: *signed}
+	if trackNonce {
+		setPendingNonce(sigTx, npa, NonceLayerConsensus, wallet.Address(), tx.Nonce)
+	}
 
-	return &consensusTx.SignedTransaction{Signed: *signed}, nil
+	return sigTx, nil
 }
 
 // SignParaTimeTransaction signs a ParaTime transaction.
@@ -229,141 +223,63 @@ func SignParaTimeTransaction(
 	wallet wallet.Account,
 	conn connection.Connection,
 	tx *types.Transaction,
+	opts ...SignOption,
 ) (*types.UnverifiedTransaction, interface{}, error) {
-	// Default to passed values and do online estimation when possible.
-	nonce := txNonce
-	tx.AuthInfo.Fee.Gas = txGasLimit
-
-	gasPrice := &types.BaseUnits{}
-	if txGasPrice != "" {
-		// TODO: Support different denominations for gas fees.
-		var err error
-		gasPrice, err = helpers.ParseParaTimeDenomination(npa.ParaTime, txGasPrice, types.NativeDenomination)
-		if err != nil {
-			return nil, nil, fmt.Errorf("bad gas price: %w", err)
-		}
-	}
-
-	if !txOffline {
-		// Query nonce if not specified.
-		if nonce == invalidNonce {
-			var err error
-			nonce, err = conn.Runtime(npa.ParaTime).Accounts.Nonce(ctx, client.RoundLatest, wallet.Address())
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to query nonce: %w", err)
-			}
-		}
-	}
-
-	// Prepare the transaction before (optional) gas estimation to ensure correct estimation.
-	tx.AppendAuthSignature(wallet.SignatureAddressSpec(), nonce)
-
-	if !txOffline { //nolint: nestif
-		// Gas estimation if not specified.
-		if tx.AuthInfo.Fee.Gas == invalidGasLimit {
-			var err error
-			tx.AuthInfo.Fee.Gas, err = conn.Runtime(npa.ParaTime).Core.EstimateGas(ctx, client.RoundLatest, tx, false)
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to estimate gas: %w", err)
-			}
-		}
-
-		// Gas price determination if not specified.
-		if txGasPrice == "" {
-			mgp, err := conn.Runtime(npa.ParaTime).Core.MinGasPrice(ctx)
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to query minimum gas price: %w", err)
-			}
-
-			// TODO: Support different denominations for gas fees.
-			denom := types.NativeDenomination
-			*gasPrice = types.NewBaseUnits(mgp[denom], denom)
-		}
-	}
-
-	// If we are using offline mode and either nonce or gas limit is not specified, abort.
-	if nonce == invalidNonce || tx.AuthInfo.Fee.Gas == invalidGasLimit {
-		return nil, nil, fmt.Errorf("nonce and/or gas limit must be specified in offline mode")
-	}
-
-	// Compute fee amount based on gas price.
-	if err := gasPrice.Amount.Mul(quantity.NewFromUint64(tx.AuthInfo.Fee.Gas)); err != nil {
-		return nil, nil, err
-	}
-	tx.AuthInfo.Fee.Amount.Amount = gasPrice.Amount
-	tx.AuthInfo.Fee.Amount.Denomination = gasPrice.Denomination
-
-	// Handle confidential transactions.
-	var meta interface{}
-	if txEncrypted {
-		// Only online mode is supported for now.
-		if txOffline {
-			return nil, nil, fmt.Errorf("encrypted transactions are not available in offline mode")
-		}
-
-		// Request public key from the runtime.
-		pk, err := conn.Runtime(npa.ParaTime).Core.CallDataPublicKey(ctx)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get runtime's call data public key: %w", err)
-		}
-
-		cfg := callformat.EncodeConfig{
-			PublicKey: &pk.PublicKey,
-		}
-		var encCall *types.Call
-		encCall, meta, err = callformat.EncodeCall(&tx.Call, types.CallFormatEncryptedX25519DeoxysII, &cfg)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to encrypt call: %w", err)
-		}
-
-		tx.Call = *encCall
-	}
-
-	PrintTransactionBeforeSigning(npa, tx)
-
-	// Sign the transaction.
-	sigCtx := signature.DeriveChainContext(npa.ParaTime.Namespace(), npa.Network.ChainContext)
-	ts := tx.PrepareForSigning()
-	if err := ts.AppendSign(sigCtx, wallet.Signer()); err != nil {
-		return nil, nil, fmt.Errorf("failed to sign transaction: %w", err)
-	}
-
-	return ts.UnverifiedTransaction(), meta, nil
+	return signParaTimeTransaction(ctx, npa, wallet, conn, tx, txNonce, newSignOptions(opts).confirm)
 }
 
-// MZ
-// SignParaTimeTransactionFaucet signs a ParaTime transaction. Temporarily used for faucet.
+// SignParaTimeTransactionWithNonce signs a ParaTime transaction using the given nonce without
+// printing the transaction and asking for confirmation. Callers are responsible for obtaining
+// confirmation from the user beforehand.
 //
 // Returns the signed transaction and call format-specific metadata for result decoding.
-func SignParaTimeTransactionFaucet(
+func SignParaTimeTransactionWithNonce(
 	ctx context.Context,
 	npa *NPASelection,
 	wallet wallet.Account,
 	conn connection.Connection,
 	tx *types.Transaction,
+	nonce uint64,
+) (*types.UnverifiedTransaction, interface{}, error) {
+	return signParaTimeTransaction(ctx, npa, wallet, conn, tx, nonce, false)
+}
+
+func signParaTimeTransaction(
+	ctx context.Context,
+	npa *NPASelection,
+	wallet wallet.Account,
+	conn connection.Connection,
+	tx *types.Transaction,
+	nonce uint64,
+	confirm bool,
 ) (*types.UnverifiedTransaction, interface{}, error) {
 	// Default to passed values and do online estimation when possible.
-	nonce := txNonce
 	tx.AuthInfo.Fee.Gas = txGasLimit
 
-	gasPrice := &types.BaseUnits{}
+	feeDenom, err := GetFeeDenomination(npa.ParaTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	strategy, err := getGasPriceStrategy()
+	if err != nil {
+		return nil, nil, err
+	}
+	gasPrice := &types.BaseUnits{Denomination: feeDenom}
 	if txGasPrice != "" {
-		// TODO: Support different denominations for gas fees.
-		var err error
-		gasPrice, err = helpers.ParseParaTimeDenomination(npa.ParaTime, txGasPrice, types.NativeDenomination)
+		gasPrice, err = helpers.ParseParaTimeDenomination(npa.ParaTime, txGasPrice, feeDenom)
 		if err != nil {
 			return nil, nil, fmt.Errorf("bad gas price: %w", err)
 		}
 	}
 
+	var trackNonce bool
 	if !txOffline {
-		// Query nonce if not specified.
+		// Query nonce if not specified, taking pending transactions into account.
 		if nonce == invalidNonce {
-			var err error
-			nonce, err = conn.Runtime(npa.ParaTime).Accounts.Nonce(ctx, client.RoundLatest, wallet.Address())
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to query nonce: %w", err)
+			if nonce, err = NextNonce(ctx, npa, conn, NonceLayer(npa), wallet.Address()); err != nil {
+				return nil, nil, err
 			}
+			trackNonce = true
 		}
 	}
 
@@ -387,10 +303,20 @@ func SignParaTimeTransactionFaucet(
 				return nil, nil, fmt.Errorf("failed to query minimum gas price: %w", err)
 			}
 
-			// TODO: Support different denominations for gas fees.
-			denom := types.NativeDenomination
-
-			*gasPrice = types.NewBaseUnits(mgp[denom], denom)
+			price, ok := mgp[feeDenom]
+			if !ok {
+				return nil, nil, fmt.Errorf("denomination '%s' cannot be used to pay fees on the ParaTime", npa.ParaTime.GetDenominationInfo(feeDenom).Symbol)
+			}
+			strategyPrice, err := strategy.resolve(price.ToBigInt(), func() ([]*big.Int, error) {
+				return paraTimeGasPrices(ctx, npa, conn, feeDenom)
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			*gasPrice = types.NewBaseUnits(types.Quantity{}, feeDenom)
+			if err = gasPrice.Amount.FromBigInt(strategyPrice); err != nil {
+				return nil, nil, err
+			}
 		}
 	}
 
@@ -406,6 +332,10 @@ func SignParaTimeTransactionFaucet(
 	tx.AuthInfo.Fee.Amount.Amount = gasPrice.Amount
 	tx.AuthInfo.Fee.Amount.Denomination = gasPrice.Denomination
 
+	if err = ForceErr(checkParaTimeFee(npa, &tx.AuthInfo.Fee.Amount)); err != nil {
+		return nil, nil, err
+	}
+
 	// Handle confidential transactions.
 	var meta interface{}
 	if txEncrypted {
@@ -432,7 +362,9 @@ func SignParaTimeTransactionFaucet(
 		tx.Call = *encCall
 	}
 
-	// PrintTransactionBeforeSigning(npa, tx)
+	if confirm {
+		PrintTransactionBeforeSigning(npa, tx)
+	}
 
 	// Sign the transaction.
 	sigCtx := signature.DeriveChainContext(npa.ParaTime.Namespace(), npa.Network.ChainContext)
@@ -440,8 +372,12 @@ func SignParaTimeTransactionFaucet(
 	if err := ts.AppendSign(sigCtx, wallet.Signer()); err != nil {
 		return nil, nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
+	sigTx := ts.UnverifiedTransaction()
+	if trackNonce {
+		setPendingNonce(sigTx, npa, NonceLayer(npa), wallet.Address(), nonce)
+	}
 
-	return ts.UnverifiedTransaction(), meta, nil
+	return sigTx, meta, nil
 }
 
 // PrintTransaction prints the transaction which can be either signed or unsigned.
@@ -534,50 +470,89 @@ func BroadcastTransaction(
 		fmt.Printf("Broadcasting transaction...\n")
 		err := conn.Consensus().SubmitTx(ctx, sigTx)
 		cobra.CheckErr(err)
+		commitPendingNonce(sigTx)
 
 		fmt.Printf("Transaction executed successfully.\n")
 		fmt.Printf("Transaction hash: %s\n", sigTx.Hash())
 	case *types.UnverifiedTransaction:
 		// ParaTime transaction.
 		fmt.Printf("Broadcasting transaction...\n")
-		rawMeta, err := conn.Runtime(pt).SubmitTxRawMeta(ctx, sigTx)
-		cobra.CheckErr(err)
-
-		if rawMeta.CheckTxError != nil {
-			cobra.CheckErr(fmt.Sprintf("Transaction check failed with error: module: %s code: %d message: %s",
-				rawMeta.CheckTxError.Module,
-				rawMeta.CheckTxError.Code,
-				rawMeta.CheckTxError.Message,
-			))
+		round, err := SubmitParaTimeTransaction(ctx, pt, conn, sigTx, meta, result)
+		if round != 0 {
+			fmt.Printf("Transaction included in block successfully.\n")
+			fmt.Printf("Round:            %d\n", round)
+			fmt.Printf("Transaction hash: %s\n", sigTx.Hash())
+
+			if meta != nil {
+				fmt.Printf("                  (Transaction result is encrypted.)\n")
+			}
 		}
+		cobra.CheckErr(err)
 
-		fmt.Printf("Transaction included in block successfully.\n")
-		fmt.Printf("Round:            %d\n", rawMeta.Round)
-		fmt.Printf("Transaction hash: %s\n", sigTx.Hash())
+		fmt.Printf("Execution successful.\n")
+	default:
+		panic(fmt.Errorf("unsupported transaction kind: %T", tx))
+	}
+}
 
-		if rawMeta.Result.IsUnknown() {
-			fmt.Printf("                  (Transaction result is encrypted.)\n")
-		}
+// SubmitConsensusTransaction submits a signed consensus transaction and waits for it to be
+// executed. Unlike BroadcastTransaction it does not print anything and returns an error instead of
+// aborting when the transaction fails.
+func SubmitConsensusTransaction(ctx context.Context, conn connection.Connection, sigTx *consensusTx.SignedTransaction) error {
+	if err := conn.Consensus().SubmitTx(ctx, sigTx); err != nil {
+		return err
+	}
+	commitPendingNonce(sigTx)
+	return nil
+}
 
-		decResult, err := callformat.DecodeResult(&rawMeta.Result, meta)
-		cobra.CheckErr(err)
+// SubmitParaTimeTransaction submits a signed ParaTime transaction and waits for it to be
+// included in a block. Unlike BroadcastTransaction it does not print anything and returns an
+// error instead of aborting when the transaction fails.
+//
+// Returns the round in which the transaction was included.
+func SubmitParaTimeTransaction(
+	ctx context.Context,
+	pt *config.ParaTime,
+	conn connection.Connection,
+	sigTx *types.UnverifiedTransaction,
+	meta interface{},
+	result interface{},
+) (uint64, error) {
+	rawMeta, err := conn.Runtime(pt).SubmitTxRawMeta(ctx, sigTx)
+	if err != nil {
+		return 0, err
+	}
+	if rawMeta.CheckTxError != nil {
+		return 0, fmt.Errorf("transaction check failed with error: module: %s code: %d message: %s",
+			rawMeta.CheckTxError.Module,
+			rawMeta.CheckTxError.Code,
+			rawMeta.CheckTxError.Message,
+		)
+	}
+	commitPendingNonce(sigTx)
 
-		switch {
-		case decResult.IsUnknown():
-			// This should never happen as the inner result should not be unknown.
-			cobra.CheckErr(fmt.Sprintf("Execution result unknown: %X", decResult.Unknown))
-		case decResult.IsSuccess():
-			fmt.Printf("Execution successful.\n")
+	return rawMeta.Round, DecodeParaTimeResult(&rawMeta.Result, meta, result)
+}
 
-			if result != nil {
-				err = cbor.Unmarshal(decResult.Ok, result)
-				cobra.CheckErr(err)
-			}
-		default:
-			cobra.CheckErr(fmt.Sprintf("Execution failed with error: %s", decResult.Failed.Error()))
+// DecodeParaTimeResult decodes the result of a ParaTime transaction given the call format-specific
+// metadata returned when signing it. When result is non-nil, the successful result is unmarshalled
+// into it.
+func DecodeParaTimeResult(callResult *types.CallResult, meta interface{}, result interface{}) error {
+	decResult, err := callformat.DecodeResult(callResult, meta)
+	if err != nil {
+		return err
+	}
+	switch {
+	case decResult.IsUnknown():
+		return fmt.Errorf("execution result unknown: %X", decResult.Unknown)
+	case decResult.IsSuccess():
+		if result != nil {
+			return cbor.Unmarshal(decResult.Ok, result)
 		}
+		return nil
 	default:
-		panic(fmt.Errorf("unsupported transaction kind: %T", tx))
+		return fmt.Errorf("execution failed with error: %s", decResult.Failed.Error())
 	}
 }
 
@@ -587,6 +562,9 @@ func BroadcastTransaction(
 // contain whatever mapFn returns.
 //
 // If mapFn is specified it should return a non-nil value when encountering a matching event.
+//
+// The wait is bounded by the timeout given via --wait-timeout. When no matching event is seen
+// before the timeout (or before the context is cancelled), the channel is closed without a value.
 func WaitForEvent(
 	ctx context.Context,
 	pt *config.ParaTime,
@@ -594,7 +572,13 @@ func WaitForEvent(
 	decoder client.EventDecoder,
 	mapFn func(client.DecodedEvent) interface{},
 ) <-chan interface{} {
-	ctx, cancel := context.WithCancel(ctx)
+	var cancel context.CancelFunc
+	switch timeout := GetWaitTimeout(); timeout {
+	case 0:
+		ctx, cancel = context.WithCancel(ctx)
+	default:
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
 
 	// Start watching events.
 	ch, err := conn.Runtime(pt).WatchEvents(ctx, []client.EventDecoder{decoder}, false)
@@ -610,15 +594,19 @@ func WaitForEvent(
 			select {
 			case <-ctx.Done():
 				return
-			case bev := <-ch:
+			case bev, ok := <-ch:
+				if !ok {
+					return
+				}
 				for _, ev := range bev.Events {
 					if result := mapFn(ev); result != nil {
-						resultCh <- result
+						select {
+						case resultCh <- result:
+						case <-ctx.Done():
+						}
 						return
 					}
 				}
-
-				// TODO: Timeout.
 			}
 		}
 	}()
@@ -632,5 +620,10 @@ func init() {
 	TransactionFlags.Uint64Var(&txNonce, "nonce", invalidNonce, "override nonce to use")
 	TransactionFlags.Uint64Var(&txGasLimit, "gas-limit", invalidGasLimit, "override gas limit to use (disable estimation)")
 	TransactionFlags.StringVar(&txGasPrice, "gas-price", "", "override gas price to use")
+	TransactionFlags.StringVar(&txGasPriceStrategy, "gas-price-strategy", gasPriceStrategyMin, "gas price strategy: min, min*<factor> or p<percentile> of gas prices paid in recent blocks")
+	TransactionFlags.StringVar(&txMaxFee, "max-fee", "", "abort signing if the fee exceeds the given amount")
+	TransactionFlags.StringVar(&txFeeDenom, "fee-denom", "", "denomination to pay ParaTime gas fees in (default: native)")
 	TransactionFlags.BoolVar(&txEncrypted, "encrypted", false, "encrypt transaction call data (requires online mode)")
+	// Fee limits and other safety checks of signing can be overridden by --force.
+	TransactionFlags.AddFlagSet(ForceFlag)
 }
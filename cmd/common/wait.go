@@ -0,0 +1,31 @@
+package common
+
+import (
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+var (
+	waitTimeout time.Duration
+	noWait      bool
+)
+
+// WaitFlags contains the flags controlling waiting for the results of submitted operations.
+var WaitFlags *flag.FlagSet
+
+// ShouldWait returns true if the user did not request to skip waiting for results.
+func ShouldWait() bool {
+	return !noWait
+}
+
+// GetWaitTimeout returns the maximum time to wait for results, or zero if there is no limit.
+func GetWaitTimeout() time.Duration {
+	return waitTimeout
+}
+
+func init() {
+	WaitFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	WaitFlags.DurationVar(&waitTimeout, "wait-timeout", 5*time.Minute, "maximum time to wait for the result (0 to wait indefinitely)")
+	WaitFlags.BoolVar(&noWait, "no-wait", false, "do not wait for the result")
+}
@@ -150,3 +150,21 @@ func CheckAddressNotReserved(cfg *config.Config, address string) error {
 
 	return nil
 }
+
+// FindAccountName finds the name of the wallet account or address book entry with the given
+// native address. Returns an empty string, if the address is not known.
+func FindAccountName(cfg *config.Config, address string) string {
+	for name, acc := range cfg.Wallet.All {
+		if acc.Address == address {
+			return name
+		}
+	}
+
+	for name, entry := range cfg.AddressBook.All {
+		if entry.Address == address {
+			return name
+		}
+	}
+
+	return ""
+}
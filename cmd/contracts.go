@@ -13,7 +13,6 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
-	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
@@ -57,7 +56,10 @@ var (
 			conn, err := connection.Connect(ctx, npa.Network)
 			cobra.CheckErr(err)
 
-			inst, err := conn.Runtime(npa.ParaTime).Contracts.Instance(ctx, client.RoundLatest, contracts.InstanceID(instanceID))
+			round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+			cobra.CheckErr(err)
+
+			inst, err := conn.Runtime(npa.ParaTime).Contracts.Instance(ctx, round, contracts.InstanceID(instanceID))
 			cobra.CheckErr(err)
 
 			fmt.Printf("ID:              %d\n", inst.ID)
@@ -87,7 +89,10 @@ var (
 			conn, err := connection.Connect(ctx, npa.Network)
 			cobra.CheckErr(err)
 
-			code, err := conn.Runtime(npa.ParaTime).Contracts.Code(ctx, client.RoundLatest, contracts.CodeID(codeID))
+			round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+			cobra.CheckErr(err)
+
+			code, err := conn.Runtime(npa.ParaTime).Contracts.Code(ctx, round, contracts.CodeID(codeID))
 			cobra.CheckErr(err)
 
 			fmt.Printf("ID:                 %d\n", code.ID)
@@ -125,12 +130,15 @@ encoded as strings, or otherwise as Base64.`,
 			conn, err := connection.Connect(ctx, npa.Network)
 			cobra.CheckErr(err)
 
+			round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+			cobra.CheckErr(err)
+
 			var storeKind contracts.StoreKind
 			cobra.CheckErr(storeKind.UnmarshalText([]byte(contractsStorageDumpKind)))
 
 			res, err := conn.Runtime(npa.ParaTime).Contracts.InstanceRawStorage(
 				ctx,
-				client.RoundLatest,
+				round,
 				contracts.InstanceID(instanceID),
 				storeKind,
 				contractsStorageDumpLimit,
@@ -179,9 +187,12 @@ otherwise as Base64.`,
 			conn, err := connection.Connect(ctx, npa.Network)
 			cobra.CheckErr(err)
 
+			round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+			cobra.CheckErr(err)
+
 			res, err := conn.Runtime(npa.ParaTime).Contracts.InstanceStorage(
 				ctx,
-				client.RoundLatest,
+				round,
 				contracts.InstanceID(instanceID),
 				key,
 			)
@@ -217,10 +228,13 @@ otherwise as Base64.`,
 			conn, err := connection.Connect(ctx, npa.Network)
 			cobra.CheckErr(err)
 
+			round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+			cobra.CheckErr(err)
+
 			// Fetch WASM contract code, if supported.
 			codeStorage, err := conn.Runtime(npa.ParaTime).Contracts.CodeStorage(
 				ctx,
-				client.RoundLatest,
+				round,
 				contracts.CodeID(codeID),
 			)
 			cobra.CheckErr(err)
@@ -519,9 +533,12 @@ func parseTokens(pt *config.ParaTime, tokens []string) []types.BaseUnits {
 
 func init() {
 	contractsShowCmd.Flags().AddFlagSet(common.SelectorFlags)
+	contractsShowCmd.Flags().AddFlagSet(common.RoundFlag)
 	contractsShowCodeCmd.Flags().AddFlagSet(common.SelectorFlags)
+	contractsShowCodeCmd.Flags().AddFlagSet(common.RoundFlag)
 
 	contractsDumpCodeCmd.Flags().AddFlagSet(common.SelectorFlags)
+	contractsDumpCodeCmd.Flags().AddFlagSet(common.RoundFlag)
 
 	contractsUploadFlags := flag.NewFlagSet("", flag.ContinueOnError)
 	contractsUploadFlags.StringVar(&contractsInstantiatePolicy, "instantiate-policy", "everyone", "contract instantiation policy")
@@ -558,9 +575,11 @@ func init() {
 	contractsStorageDumpCmdFlags.Uint64Var(&contractsStorageDumpLimit, "limit", 0, "result set limit")
 	contractsStorageDumpCmdFlags.Uint64Var(&contractsStorageDumpOffset, "offset", 0, "result set offset")
 	contractsStorageDumpCmd.Flags().AddFlagSet(common.SelectorFlags)
+	contractsStorageDumpCmd.Flags().AddFlagSet(common.RoundFlag)
 	contractsStorageDumpCmd.Flags().AddFlagSet(contractsStorageDumpCmdFlags)
 
 	contractsStorageGetCmd.Flags().AddFlagSet(common.SelectorFlags)
+	contractsStorageGetCmd.Flags().AddFlagSet(common.RoundFlag)
 
 	contractsStorageCmd.AddCommand(contractsStorageDumpCmd)
 	contractsStorageCmd.AddCommand(contractsStorageGetCmd)
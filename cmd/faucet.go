@@ -0,0 +1,516 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+	"github.com/oasisprotocol/cli/wallet"
+)
+
+var (
+	faucetListen          string
+	faucetConsensusAmount string
+	faucetParaTimeAmount  string
+	faucetConsensusBudget string
+	faucetParaTimeBudget  string
+	faucetAddressInterval time.Duration
+	faucetIPLimit         uint
+	faucetLogFile         string
+	faucetTrustProxy      bool
+
+	faucetCmd = &cobra.Command{
+		Use:   "faucet",
+		Short: "Test token faucet",
+	}
+)
+
+// errFaucetBadRequest is returned when a request is malformed.
+var errFaucetBadRequest = errors.New("bad request")
+
+// errFaucetRateLimited is returned when a request exceeds the per-address or per-IP limits.
+var errFaucetRateLimited = errors.New("rate limited")
+
+// errFaucetBudgetExhausted is returned when the daily budget does not cover a request.
+var errFaucetBudgetExhausted = errors.New("daily budget exhausted")
+
+// faucetDispenser transfers tokens to recipients.
+type faucetDispenser interface {
+	// Dispense transfers the given amount of base units on the given layer and returns the
+	// transaction hash.
+	Dispense(ctx context.Context, layer string, to types.Address, amount quantity.Quantity) (string, error)
+
+	// Health checks that the node is reachable.
+	Health(ctx context.Context) error
+}
+
+// faucetLimits are the limits of the faucet for a single layer.
+type faucetLimits struct {
+	// Amount is the amount of base units dispensed per request.
+	Amount quantity.Quantity
+	// Budget is the amount of base units that may be dispensed per UTC day. Zero means unlimited.
+	Budget quantity.Quantity
+}
+
+// faucetLogEntry is a single entry of the persistent faucet request log.
+type faucetLogEntry struct {
+	Time    time.Time         `json:"time"`
+	IP      string            `json:"ip"`
+	Address string            `json:"address"`
+	Layer   string            `json:"layer"`
+	Amount  quantity.Quantity `json:"amount"`
+	TxHash  string            `json:"tx_hash,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// faucetServer is the HTTP faucet service.
+type faucetServer struct {
+	sync.Mutex
+
+	dispenser       faucetDispenser
+	limits          map[string]*faucetLimits
+	addressInterval time.Duration
+	ipLimit         uint
+	trustProxy      bool
+	resolveAddress  func(string) (*types.Address, error)
+	now             func() time.Time
+
+	log     *os.File
+	entries []*faucetLogEntry
+}
+
+// newFaucetServer creates a new faucet server. Successful requests found in the request log are
+// taken into account for the rate limits and the daily budget.
+func newFaucetServer(dispenser faucetDispenser, limits map[string]*faucetLimits, logFile string) (*faucetServer, error) {
+	s := &faucetServer{
+		dispenser: dispenser,
+		limits:    limits,
+		now:       time.Now,
+		resolveAddress: func(addr string) (*types.Address, error) {
+			a, _, err := helpers.ResolveEthOrOasisAddress(addr)
+			if err == nil && a == nil {
+				err = fmt.Errorf("unsupported address format")
+			}
+			return a, err
+		},
+	}
+
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open request log: %w", err)
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry faucetLogEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("malformed request log entry: %w", err)
+		}
+		if entry.Error == "" {
+			s.entries = append(s.entries, &entry)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read request log: %w", err)
+	}
+	s.log = f
+	return s, nil
+}
+
+// Close closes the request log.
+func (s *faucetServer) Close() error {
+	return s.log.Close()
+}
+
+// spentToday returns the amount dispensed on the given layer since the start of the UTC day.
+func (s *faucetServer) spentToday(layer string) quantity.Quantity {
+	now := s.now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var spent quantity.Quantity
+	for _, entry := range s.entries {
+		if entry.Layer == layer && !entry.Time.Before(dayStart) {
+			_ = spent.Add(&entry.Amount)
+		}
+	}
+	return spent
+}
+
+// checkLimits checks the rate limits and the daily budget of a request.
+func (s *faucetServer) checkLimits(ip, addr, layer string) error {
+	now := s.now()
+	var ipRequests uint
+	for _, entry := range s.entries {
+		if entry.Address == addr && s.addressInterval > 0 && now.Sub(entry.Time) < s.addressInterval {
+			return fmt.Errorf("%w: address %s was funded at %s", errFaucetRateLimited, addr, entry.Time.UTC().Format(time.RFC3339))
+		}
+		if entry.IP == ip && now.Sub(entry.Time) < 24*time.Hour {
+			ipRequests++
+		}
+	}
+	if s.ipLimit > 0 && ipRequests >= s.ipLimit {
+		return fmt.Errorf("%w: %d requests from %s in the last 24 hours", errFaucetRateLimited, ipRequests, ip)
+	}
+
+	limits := s.limits[layer]
+	if limits.Budget.IsZero() {
+		return nil
+	}
+	spent := s.spentToday(layer)
+	if err := spent.Add(&limits.Amount); err != nil {
+		return err
+	}
+	if spent.Cmp(&limits.Budget) > 0 {
+		return errFaucetBudgetExhausted
+	}
+	return nil
+}
+
+// appendLog appends the given entry to the request log.
+func (s *faucetServer) appendLog(entry *faucetLogEntry) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err = s.log.Write(append(raw, '\n')); err != nil {
+		return fmt.Errorf("failed to write request log: %w", err)
+	}
+	return nil
+}
+
+// removeEntry removes the given entry from the entries counted towards the limits.
+func (s *faucetServer) removeEntry(entry *faucetLogEntry) {
+	for i, e := range s.entries {
+		if e == entry {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// fund handles a single funding request. The limits are checked and the request is reserved
+// atomically so that rate limits and the budget stay consistent under concurrent requests.
+func (s *faucetServer) fund(ctx context.Context, ip, address, layer string) (*faucetLogEntry, error) {
+	limits, ok := s.limits[layer]
+	if !ok {
+		return nil, fmt.Errorf("%w: layer '%s' is not served by this faucet", errFaucetBadRequest, layer)
+	}
+	addr, err := s.resolveAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad address: %s", errFaucetBadRequest, err)
+	}
+
+	s.Lock()
+	if err = s.checkLimits(ip, addr.String(), layer); err != nil {
+		s.Unlock()
+		return nil, err
+	}
+
+	// Count the request towards the limits while the transfer is in flight, so that the lock
+	// does not need to be held while waiting for the transfer.
+	entry := &faucetLogEntry{
+		Time:    s.now().UTC(),
+		IP:      ip,
+		Address: addr.String(),
+		Layer:   layer,
+		Amount:  limits.Amount,
+	}
+	s.entries = append(s.entries, entry)
+	s.Unlock()
+
+	txHash, err := s.dispenser.Dispense(ctx, layer, *addr, limits.Amount)
+
+	s.Lock()
+	defer s.Unlock()
+
+	entry.TxHash = txHash
+	if err != nil {
+		entry.Error = err.Error()
+		s.removeEntry(entry)
+	}
+	if logErr := s.appendLog(entry); logErr != nil {
+		return nil, logErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("transfer failed: %w", err)
+	}
+	return entry, nil
+}
+
+// clientIP returns the IP address of the client of the given request. Behind a trusted proxy it is
+// the right-most X-Forwarded-For entry, which is the one appended by the proxy. Earlier entries
+// are supplied by the client and cannot be trusted.
+func (s *faucetServer) clientIP(r *http.Request) string {
+	if s.trustProxy {
+		if fwd := r.Header.Values("X-Forwarded-For"); len(fwd) > 0 {
+			hops := strings.Split(fwd[len(fwd)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeJSON writes the given value as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// handleFund handles POST /fund requests with a JSON body containing the address and the layer.
+func (s *faucetServer) handleFund(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var req struct {
+		Address string `json:"address"`
+		Layer   string `json:"layer"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "malformed request: " + err.Error()})
+		return
+	}
+	if req.Layer == "" {
+		req.Layer = layerParaTime
+		if _, ok := s.limits[req.Layer]; !ok {
+			req.Layer = layerConsensus
+		}
+	}
+
+	entry, err := s.fund(r.Context(), s.clientIP(r), req.Address, strings.ToLower(req.Layer))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, entry)
+	case errors.Is(err, errFaucetRateLimited):
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	case errors.Is(err, errFaucetBudgetExhausted):
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+	case errors.Is(err, errFaucetBadRequest):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+	}
+}
+
+// handleHealth handles GET /health requests.
+func (s *faucetServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	remaining := make(map[string]string)
+	for layer, limits := range s.limits {
+		if limits.Budget.IsZero() {
+			remaining[layer] = "unlimited"
+			continue
+		}
+		left := limits.Budget.Clone()
+		spent := s.spentToday(layer)
+		if left.Sub(&spent) != nil {
+			left = quantity.NewQuantity()
+		}
+		remaining[layer] = left.String()
+	}
+	s.Unlock()
+
+	status, code, errMsg := "ok", http.StatusOK, ""
+	if err := s.dispenser.Health(r.Context()); err != nil {
+		status, code, errMsg = "unhealthy", http.StatusServiceUnavailable, err.Error()
+	}
+	writeJSON(w, code, map[string]interface{}{
+		"status":           status,
+		"error":            errMsg,
+		"budget_remaining": remaining,
+	})
+}
+
+// Handler returns the HTTP handler of the faucet.
+func (s *faucetServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fund", s.handleFund)
+	mux.HandleFunc("/health", s.handleHealth)
+	return mux
+}
+
+// nodeDispenser dispenses tokens by signing and submitting transfers through a node.
+type nodeDispenser struct {
+	sync.Mutex
+
+	npa  *common.NPASelection
+	acc  wallet.Account
+	conn connection.Connection
+}
+
+func (d *nodeDispenser) Dispense(ctx context.Context, layer string, to types.Address, amount quantity.Quantity) (string, error) {
+	// Transfers are signed and submitted one at a time so that they use consecutive nonces.
+	d.Lock()
+	defer d.Unlock()
+
+	switch layer {
+	case layerConsensus:
+		tx := staking.NewTransferTx(0, nil, &staking.Transfer{
+			To:     to.ConsensusAddress(),
+			Amount: amount,
+		})
+		sigTx, err := common.SignConsensusTransaction(ctx, d.npa, d.acc, d.conn, tx, common.WithoutConfirmation())
+		if err != nil {
+			return "", err
+		}
+		if err = common.SubmitConsensusTransaction(ctx, d.conn, sigTx); err != nil {
+			return "", err
+		}
+		return sigTx.Hash().String(), nil
+	case layerParaTime:
+		tx := accounts.NewTransferTx(nil, &accounts.Transfer{
+			To:     to,
+			Amount: types.NewBaseUnits(amount, types.NativeDenomination),
+		})
+		sigTx, meta, err := common.SignParaTimeTransaction(ctx, d.npa, d.acc, d.conn, tx, common.WithoutConfirmation())
+		if err != nil {
+			return "", err
+		}
+		if _, err = common.SubmitParaTimeTransaction(ctx, d.npa.ParaTime, d.conn, sigTx, meta, nil); err != nil {
+			return "", err
+		}
+		return sigTx.Hash().String(), nil
+	default:
+		return "", fmt.Errorf("unsupported layer '%s'", layer)
+	}
+}
+
+func (d *nodeDispenser) Health(ctx context.Context) error {
+	if _, err := d.conn.Consensus().GetBlock(ctx, consensus.HeightLatest); err != nil {
+		return fmt.Errorf("consensus layer: %w", err)
+	}
+	if d.npa.ParaTime != nil {
+		if _, err := d.conn.Runtime(d.npa.ParaTime).GetBlock(ctx, client.RoundLatest); err != nil {
+			return fmt.Errorf("paratime: %w", err)
+		}
+	}
+	return nil
+}
+
+var faucetServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a faucet dispensing tokens from the selected account over HTTP",
+	Long: "Serve a faucet over HTTP. POST /fund with a JSON body {\"address\": ..., \"layer\": \"consensus\"|\"paratime\"} " +
+		"transfers the configured amount to the address. GET /health reports the node status and the " +
+		"remaining daily budget. Requests are rate limited per address and per client IP and are recorded " +
+		"in a persistent request log, which is also used to restore the limits after a restart.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+		txCfg := common.GetTransactionConfig()
+
+		if npa.Account == nil {
+			cobra.CheckErr("no accounts configured in your wallet")
+		}
+		if txCfg.Offline {
+			cobra.CheckErr("the faucet is not available in offline mode")
+		}
+
+		limits := make(map[string]*faucetLimits)
+		if faucetConsensusAmount != "" {
+			amount, err := helpers.ParseConsensusDenomination(npa.Network, faucetConsensusAmount)
+			cobra.CheckErr(err)
+			budget := quantity.NewQuantity()
+			if faucetConsensusBudget != "" {
+				budget, err = helpers.ParseConsensusDenomination(npa.Network, faucetConsensusBudget)
+				cobra.CheckErr(err)
+			}
+			limits[layerConsensus] = &faucetLimits{Amount: *amount, Budget: *budget}
+		}
+		if faucetParaTimeAmount != "" {
+			if npa.ParaTime == nil {
+				cobra.CheckErr("--paratime-amount requires a runtime")
+			}
+			amount, err := helpers.ParseParaTimeDenomination(npa.ParaTime, faucetParaTimeAmount, types.NativeDenomination)
+			cobra.CheckErr(err)
+			budget := &types.BaseUnits{}
+			if faucetParaTimeBudget != "" {
+				budget, err = helpers.ParseParaTimeDenomination(npa.ParaTime, faucetParaTimeBudget, types.NativeDenomination)
+				cobra.CheckErr(err)
+			}
+			limits[layerParaTime] = &faucetLimits{Amount: amount.Amount, Budget: budget.Amount}
+		}
+		if len(limits) == 0 {
+			cobra.CheckErr("at least one of --consensus-amount and --paratime-amount is required")
+		}
+
+		logFile := faucetLogFile
+		if logFile == "" {
+			logFile = filepath.Join(cliConfig.Directory(), "faucet.log")
+		}
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		acc := common.LoadAccount(cfg, npa.AccountName)
+
+		s, err := newFaucetServer(&nodeDispenser{npa: npa, acc: acc, conn: conn}, limits, logFile)
+		cobra.CheckErr(err)
+		defer s.Close()
+		s.addressInterval = faucetAddressInterval
+		s.ipLimit = faucetIPLimit
+		s.trustProxy = faucetTrustProxy
+
+		fmt.Printf("Serving faucet from account '%s' on %s (request log: %s).\n", npa.AccountName, faucetListen, logFile)
+		server := &http.Server{
+			Addr:              faucetListen,
+			Handler:           s.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		cobra.CheckErr(server.ListenAndServe())
+	},
+}
+
+func init() {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.StringVar(&faucetListen, "listen", "127.0.0.1:8080", "address to listen on")
+	f.StringVar(&faucetConsensusAmount, "consensus-amount", "", "amount dispensed per request on the consensus layer (disabled if empty)")
+	f.StringVar(&faucetParaTimeAmount, "paratime-amount", "", "amount dispensed per request on the ParaTime (disabled if empty)")
+	f.StringVar(&faucetConsensusBudget, "consensus-budget", "", "maximum amount dispensed per UTC day on the consensus layer (unlimited if empty)")
+	f.StringVar(&faucetParaTimeBudget, "paratime-budget", "", "maximum amount dispensed per UTC day on the ParaTime (unlimited if empty)")
+	f.DurationVar(&faucetAddressInterval, "address-interval", 24*time.Hour, "minimum time between two fundings of the same address")
+	f.UintVar(&faucetIPLimit, "ip-limit", 10, "maximum number of fundings per client IP in 24 hours (0 for unlimited)")
+	f.StringVar(&faucetLogFile, "log", "", "persistent request log (default: faucet.log in the config directory)")
+	f.BoolVar(&faucetTrustProxy, "trust-proxy", false, "take the client IP from the right-most X-Forwarded-For entry set by a reverse proxy")
+
+	faucetServeCmd.Flags().AddFlagSet(common.SelectorFlags)
+	faucetServeCmd.Flags().AddFlagSet(common.TransactionFlags)
+	faucetServeCmd.Flags().AddFlagSet(f)
+
+	faucetCmd.AddCommand(faucetServeCmd)
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// testDispenser is a stand-in node recording the dispensed transfers.
+type testDispenser struct {
+	transfers []string
+	fail      bool
+}
+
+func (d *testDispenser) Dispense(ctx context.Context, layer string, to types.Address, amount quantity.Quantity) (string, error) {
+	if d.fail {
+		return "", fmt.Errorf("node unavailable")
+	}
+	d.transfers = append(d.transfers, layer)
+	return fmt.Sprintf("hash-%d", len(d.transfers)), nil
+}
+
+func (d *testDispenser) Health(ctx context.Context) error {
+	if d.fail {
+		return fmt.Errorf("node unavailable")
+	}
+	return nil
+}
+
+func TestFaucetServer(t *testing.T) {
+	require := require.New(t)
+
+	logFile := filepath.Join(t.TempDir(), "faucet.log")
+	limits := map[string]*faucetLimits{
+		layerConsensus: {Amount: *quantity.NewFromUint64(10), Budget: *quantity.NewFromUint64(25)},
+	}
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	newServer := func(d *testDispenser) (*faucetServer, *httptest.Server) {
+		s, err := newFaucetServer(d, limits, logFile)
+		require.NoError(err)
+		s.ipLimit = 2
+		s.now = func() time.Time { return now }
+		s.resolveAddress = func(string) (*types.Address, error) { return &types.Address{}, nil }
+		return s, httptest.NewServer(s.Handler())
+	}
+	fund := func(url, ip, layer string) int {
+		req, err := http.NewRequest(http.MethodPost, url+"/fund", bytes.NewBufferString(fmt.Sprintf(`{"address": "x", "layer": "%s"}`, layer)))
+		require.NoError(err)
+		req.Header.Set("X-Forwarded-For", ip)
+		rsp, err := http.DefaultClient.Do(req)
+		require.NoError(err)
+		rsp.Body.Close()
+		return rsp.StatusCode
+	}
+
+	d := &testDispenser{}
+	s, ts := newServer(d)
+	s.trustProxy = true
+
+	require.Equal(http.StatusOK, fund(ts.URL, "1.1.1.1", ""), "default layer")
+	require.Equal(http.StatusBadRequest, fund(ts.URL, "1.1.1.1", layerParaTime), "layer not served")
+	require.Equal(http.StatusOK, fund(ts.URL, "1.1.1.1", layerConsensus))
+	require.Equal(http.StatusTooManyRequests, fund(ts.URL, "1.1.1.1", layerConsensus), "per-IP limit")
+	require.Equal(http.StatusServiceUnavailable, fund(ts.URL, "2.2.2.2", layerConsensus), "daily budget")
+	require.Len(d.transfers, 2)
+
+	rsp, err := http.Get(ts.URL + "/health")
+	require.NoError(err)
+	rsp.Body.Close()
+	require.Equal(http.StatusOK, rsp.StatusCode)
+
+	ts.Close()
+	require.NoError(s.Close())
+
+	// The request log restores the limits after a restart and failed transfers are not counted.
+	now = now.Add(24 * time.Hour)
+	d = &testDispenser{fail: true}
+	s, ts = newServer(d)
+	s.addressInterval = 48 * time.Hour
+	require.Equal(http.StatusTooManyRequests, fund(ts.URL, "3.3.3.3", layerConsensus), "per-address limit")
+	s.addressInterval = 0
+	require.Equal(http.StatusBadGateway, fund(ts.URL, "3.3.3.3", layerConsensus), "transfer failure")
+	require.Len(s.entries, 2)
+
+	rsp, err = http.Get(ts.URL + "/health")
+	require.NoError(err)
+	rsp.Body.Close()
+	require.Equal(http.StatusServiceUnavailable, rsp.StatusCode)
+
+	ts.Close()
+	require.NoError(s.Close())
+}
+
+func TestFaucetClientIP(t *testing.T) {
+	require := require.New(t)
+
+	s := &faucetServer{}
+	req := httptest.NewRequest(http.MethodPost, "/fund", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "6.6.6.6, 1.1.1.1")
+	require.Equal("10.0.0.1", s.clientIP(req), "untrusted proxy")
+
+	s.trustProxy = true
+	require.Equal("1.1.1.1", s.clientIP(req), "client supplied entries are ignored")
+}
@@ -6,15 +6,12 @@ import (
 	"strings"
 	"strconv"
 	"io/ioutil"
-	"encoding/json"
 
 	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
 
-	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
-	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
-	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 
@@ -23,6 +20,7 @@ import (
 )
 
 var (
+	initOwnersFile string
 
 	managestCmd = &cobra.Command{
 		Use:   "managest",
@@ -32,90 +30,74 @@ var (
 	managestShowPropCmd = &cobra.Command{
 		Use:   "showproposal [ID]",
 		Short: "Show proposal information with proposal ID, latest proposal is output by default",
-		Args:  cobra.MinimumNArgs(0),
+		Long: "Show proposal information with proposal ID, latest proposal is output by default.\n\n" +
+			"With --format json or yaml the proposal is output as an object with the fields id, submitter, " +
+			"state, action, content (decoded proposal data fields) and results (vote option to count).",
+		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cfg := cliConfig.Global()
-			npa := common.GetNPASelection(cfg)				
-
-			if npa.ParaTime != nil {
-				// Establish connection with the target network.
-				ctx := context.Background()
-				c, err := connection.Connect(ctx, npa.Network)
-				cobra.CheckErr(err)
-
-				height, err := common.GetActualHeight(
-					ctx,
-					c.Consensus(),
-				)
-				cobra.CheckErr(err)
-
-				// Make an effort to support the height query.
-				//
-				// Note: Public gRPC endpoints do not allow this method.
-				round := client.RoundLatest
-				if h := common.GetHeight(); h != consensus.HeightLatest {
-					blk, err := c.Consensus().RootHash().GetLatestBlock(
-						ctx,
-						&roothash.RuntimeRequest{
-							RuntimeID: npa.ParaTime.Namespace(),
-							Height:    height,
-						},
-					)
-					cobra.CheckErr(err)
-					round = blk.Header.Round
-				}
+			npa := common.GetNPASelection(cfg)
+			format := common.GetOutputFormat()
 
-				var proposalID uint32
-				if len(args) > 0 {
-					prop_id_input := args[0]
-					parsedID, err := strconv.ParseUint(prop_id_input, 10, 32)
-					if err != nil {
-						// Handle the error, e.g., print an error message and return
-						fmt.Printf("Invalid proposal ID: %s\n", prop_id_input)
-						return
-					}
-					// Use the parsed ID
-					proposalID = uint32(parsedID)
+			if npa.ParaTime == nil {
+				cobra.CheckErr("no paratime specified")
+			}
 
-				}else{
-					proposalID, err = c.Runtime(npa.ParaTime).Accounts.ProposalIDInfo(ctx, round)
-					cobra.CheckErr(err)
+			var proposalID uint32
+			if len(args) > 0 {
+				parsedID, err := strconv.ParseUint(args[0], 10, 32)
+				if err != nil {
+					cobra.CheckErr(fmt.Errorf("invalid proposal ID: %s", args[0]))
 				}
+				proposalID = uint32(parsedID)
+			}
 
+			// Establish connection with the target network.
+			ctx := context.Background()
+			c, err := connection.Connect(ctx, npa.Network)
+			cobra.CheckErr(err)
 
-				fmt.Println()
-				fmt.Printf("=== %s PARATIME ===\n", npa.ParaTimeName)
-				fmt.Printf("Queried proposal ID is: %d. \n", proposalID)
+			round, err := common.GetActualRound(ctx, c, npa.ParaTime)
+			cobra.CheckErr(err)
 
-				// Query runtime proposal status when a paratime has been configured.
-				proposal, err := c.Runtime(npa.ParaTime).Accounts.ProposalInfo(ctx, round, proposalID)
+			if len(args) == 0 {
+				proposalID, err = c.Runtime(npa.ParaTime).Accounts.ProposalIDInfo(ctx, round)
 				cobra.CheckErr(err)
+			}
 
-				if proposal.Content.Action != types.NoAction {
-					fmt.Printf("===================Proposal============================\n")
-					fmt.Printf("Proposal ID: %d\n", proposal.ID)
-					fmt.Printf("Proposal Submitter: %s\n", proposal.Submitter.String())
-					fmt.Printf("Proposal State: %s\n", proposal.State.String())
-					fmt.Printf("Proposal Content:\n")
-				    contentStr, err := proposal.Content.String()
-					cobra.CheckErr(err)
-					for key, value := range contentStr {
-						fmt.Printf("    %s: %s\n", key, value)
-					}
+			// Query runtime proposal status when a paratime has been configured.
+			proposal, err := fetchProposal(ctx, c, npa.ParaTime, round, proposalID)
+			cobra.CheckErr(err)
 
-				    if len(proposal.Results) > 0 {
-				        fmt.Println("Results:")
-				        for vote, count := range proposal.Results {
-				            fmt.Printf("    Vote: %s, Count: %d\n", vote.String(), count)
-				        }
-				    }
-					fmt.Printf("=====================================================\n")
+			if format != common.FormatTable {
+				if proposal == nil {
+					cobra.CheckErr(fmt.Errorf("proposal %d does not exist", proposalID))
 				}
+				common.PrintStructured(format, proposal)
+				return
+			}
 
+			fmt.Println()
+			fmt.Printf("=== %s PARATIME ===\n", npa.ParaTimeName)
+			fmt.Printf("Queried proposal ID is: %d. \n", proposalID)
+
+			if proposal != nil {
+				fmt.Printf("===================Proposal============================\n")
+				fmt.Printf("Proposal ID: %d\n", proposal.ID)
+				fmt.Printf("Proposal Submitter: %s\n", proposal.Submitter.String())
+				fmt.Printf("Proposal State: %s\n", proposal.State)
+				fmt.Printf("Proposal Content:\n")
+				for _, key := range sortedKeys(proposal.Content) {
+					fmt.Printf("    %s: %s\n", key, proposal.Content[key])
+				}
 
-
-			}else{
-				fmt.Println("No paratime specified!")
+				if len(proposal.Results) > 0 {
+					fmt.Println("Results:")
+					for _, vote := range sortedKeys(proposal.Results) {
+						fmt.Printf("    Vote: %s, Count: %d\n", vote, proposal.Results[vote])
+					}
+				}
+				fmt.Printf("=====================================================\n")
 			}
 		},
 	}
@@ -124,66 +106,59 @@ var (
 	managestShowRolesCmd = &cobra.Command{
 		Use:   "showroles [role]",
 		Short: "Show accounts of specific roles, including Admin, MintProposer, MintVoter etc.",
-		Args:  cobra.MinimumNArgs(0),
+		Long: "Show accounts of specific roles, including Admin, MintProposer, MintVoter etc.\n\n" +
+			"With --format json or yaml the roles are output as an object mapping each role name to the " +
+			"list of member addresses. Roles without members map to an empty list.",
+		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cfg := cliConfig.Global()
-			npa := common.GetNPASelection(cfg)				
+			npa := common.GetNPASelection(cfg)
+			format := common.GetOutputFormat()
 
-			if npa.ParaTime != nil {
-				// Establish connection with the target network.
-				ctx := context.Background()
-				c, err := connection.Connect(ctx, npa.Network)
-				cobra.CheckErr(err)
+			if npa.ParaTime == nil {
+				cobra.CheckErr("no paratime specified")
+			}
 
-				height, err := common.GetActualHeight(
-					ctx,
-					c.Consensus(),
-				)
+			roles := make([]types.Role, 0, types.User)
+			if len(args) > 0 {
+				role, err := types.RoleFromString(args[0])
 				cobra.CheckErr(err)
-
-				// Make an effort to support the height query.
-				//
-				// Note: Public gRPC endpoints do not allow this method.
-				round := client.RoundLatest
-				if h := common.GetHeight(); h != consensus.HeightLatest {
-					blk, err := c.Consensus().RootHash().GetLatestBlock(
-						ctx,
-						&roothash.RuntimeRequest{
-							RuntimeID: npa.ParaTime.Namespace(),
-							Height:    height,
-						},
-					)
-					cobra.CheckErr(err)
-					round = blk.Header.Round
+				roles = append(roles, role)
+			} else {
+				for role := types.Admin; role < types.User; role++ {
+					roles = append(roles, role)
 				}
+			}
 
+			// Establish connection with the target network.
+			ctx := context.Background()
+			c, err := connection.Connect(ctx, npa.Network)
+			cobra.CheckErr(err)
 
+			round, err := common.GetActualRound(ctx, c, npa.ParaTime)
+			cobra.CheckErr(err)
 
-				fmt.Println()
-				fmt.Printf("=== %s PARATIME ===\n", npa.ParaTimeName)
-
-				if len(args) > 0 {
-					roleStr := args[0]
-					role, err := types.RoleFromString(roleStr)
-					cobra.CheckErr(err)
+			teams := make(map[string][]types.Address)
+			for _, role := range roles {
+				addrs, err := c.Runtime(npa.ParaTime).Accounts.RolesTeam(ctx, round, role)
+				cobra.CheckErr(err)
+				if addrs == nil {
+					addrs = []types.Address{}
+				}
+				teams[role.String()] = addrs
+			}
 
-					addrs, err := c.Runtime(npa.ParaTime).Accounts.RolesTeam(ctx, round, role)
-					if len(addrs) > 0 {
-						fmt.Printf("%s: %s\n", roleStr, addrs)
-					}
+			if format != common.FormatTable {
+				common.PrintStructured(format, teams)
+				return
+			}
 
-				}else{
-					for role := types.Admin; role < types.User; role++ {
-						addrs, err := c.Runtime(npa.ParaTime).Accounts.RolesTeam(ctx, round, role)
-						cobra.CheckErr(err)
-						if len(addrs) > 0 {
-							fmt.Printf("%s: %s\n", role.String(), addrs)
-						}
-					}
+			fmt.Println()
+			fmt.Printf("=== %s PARATIME ===\n", npa.ParaTimeName)
+			for _, role := range roles {
+				if addrs := teams[role.String()]; len(addrs) > 0 {
+					fmt.Printf("%s: %s\n", role.String(), addrs)
 				}
-
-			}else{
-				fmt.Println("No paratime specified!")
 			}
 		},
 	}
@@ -192,68 +167,57 @@ var (
 	managestShowQuorumsCmd = &cobra.Command{
 		Use:   "showquorums [action]",
 		Short: "Show quorums of different actions, including Mint, Burn, SetRoles, Config, etc.",
-		Args:  cobra.MinimumNArgs(0),
+		Long: "Show quorums of different actions, including Mint, Burn, SetRoles, Config, etc.\n\n" +
+			"With --format json or yaml the quorums are output as an object mapping each action name to its " +
+			"quorum in percent of the voter team.",
+		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			cfg := cliConfig.Global()
-			npa := common.GetNPASelection(cfg)				
+			npa := common.GetNPASelection(cfg)
+			format := common.GetOutputFormat()
 
-			if npa.ParaTime != nil {
-				// Establish connection with the target network.
-				ctx := context.Background()
-				c, err := connection.Connect(ctx, npa.Network)
-				cobra.CheckErr(err)
+			if npa.ParaTime == nil {
+				cobra.CheckErr("no paratime specified")
+			}
 
-				height, err := common.GetActualHeight(
-					ctx,
-					c.Consensus(),
-				)
+			var actions []types.Action
+			if len(args) > 0 {
+				action, err := types.ActionFromString(args[0])
 				cobra.CheckErr(err)
-
-				// Make an effort to support the height query.
-				//
-				// Note: Public gRPC endpoints do not allow this method.
-				round := client.RoundLatest
-				if h := common.GetHeight(); h != consensus.HeightLatest {
-					blk, err := c.Consensus().RootHash().GetLatestBlock(
-						ctx,
-						&roothash.RuntimeRequest{
-							RuntimeID: npa.ParaTime.Namespace(),
-							Height:    height,
-						},
-					)
-					cobra.CheckErr(err)
-					round = blk.Header.Round
+				actions = append(actions, action)
+			} else {
+				for action := types.SetRoles; action <= types.Config; action++ {
+					actions = append(actions, action)
 				}
+			}
 
+			// Establish connection with the target network.
+			ctx := context.Background()
+			c, err := connection.Connect(ctx, npa.Network)
+			cobra.CheckErr(err)
 
+			round, err := common.GetActualRound(ctx, c, npa.ParaTime)
+			cobra.CheckErr(err)
 
-				fmt.Println()
-				fmt.Printf("=== %s PARATIME ===\n", npa.ParaTimeName)
-				fmt.Printf("Quorums are: \n")
-
-				if len(args) > 0 {
-					actionStr := args[0]
-					action, err := types.ActionFromString(actionStr)
-					cobra.CheckErr(err)
+			quorums := make(map[string]uint8)
+			for _, action := range actions {
+				quorum, err := c.Runtime(npa.ParaTime).Accounts.Quorums(ctx, round, action)
+				cobra.CheckErr(err)
+				quorums[action.String()] = quorum
+			}
 
-					quorum, err := c.Runtime(npa.ParaTime).Accounts.Quorums(ctx, round, action)
-					cobra.CheckErr(err)
-					if quorum != 0 {
-						fmt.Printf("%s: %d%%\n", actionStr, quorum)
-					}
+			if format != common.FormatTable {
+				common.PrintStructured(format, quorums)
+				return
+			}
 
-				}else{
-					for action := types.SetRoles; action <= types.Config; action++ {
-						quorum, err := c.Runtime(npa.ParaTime).Accounts.Quorums(ctx, round, action)
-						cobra.CheckErr(err)
-						if quorum != 0 {
-							fmt.Printf("%s: %d%%\n", action.String(), quorum)
-						}
-					}
+			fmt.Println()
+			fmt.Printf("=== %s PARATIME ===\n", npa.ParaTimeName)
+			fmt.Printf("Quorums are: \n")
+			for _, action := range actions {
+				if quorum := quorums[action.String()]; quorum != 0 {
+					fmt.Printf("%s: %d%%\n", action.String(), quorum)
 				}
-
-			}else{
-				fmt.Println("No paratime specified!")
 			}
 		},
 	}
@@ -262,8 +226,20 @@ var (
 	managestInitOwnersCmd = &cobra.Command{
 		Use:   "initowners [addr1 role1] [addr2 role2] ...",
 		Short: "Initialize addresses with roles",
-		Long: "Init owners by chain_initiator only one time, roles are [Admin, MintProposer, MintVoter, ...].",
-		Args:  cobra.ArbitraryArgs,
+		Long: "Init owners by chain_initiator only one time, roles are [Admin, MintProposer, MintVoter, ...].\n\n" +
+			"Instead of address and role pairs, the role teams can be given in a TOML or YAML file using " +
+			"--from-file. The file maps role names to lists of account names, address book names or addresses. " +
+			"An address can only have one role:\n\n" +
+			"  " + strings.ReplaceAll(strings.TrimSpace(initOwnersRolesExample), "\n", "\n  "),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if initOwnersFile != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			if len(args) == 0 || len(args)%2 != 0 {
+				return fmt.Errorf("expected address and role pairs or --from-file")
+			}
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cfg := cliConfig.Global()
 			npa := common.GetNPASelection(cfg)
@@ -272,55 +248,56 @@ var (
 			if npa.Account == nil {
 				cobra.CheckErr("no accounts configured in your wallet")
 			}
+			if npa.ParaTime == nil {
+				cobra.CheckErr(fmt.Errorf("Invalid paratime configured!"))
+			}
+
+			var members []*initOwnersMember
+			if initOwnersFile != "" {
+				var errs []error
+				members, errs = loadRolesFile(npa, initOwnersFile)
+				if len(errs) > 0 {
+					fmt.Printf("Roles file is invalid:\n")
+					for _, err := range errs {
+						fmt.Printf("  - %s\n", err)
+					}
+					cobra.CheckErr(fmt.Errorf("%d problem(s) found", len(errs)))
+				}
+			} else {
+				var err error
+				members, err = parseInitOwnersArgs(npa, args)
+				cobra.CheckErr(err)
+			}
 
 			// When not in offline mode, connect to the given network endpoint.
 			ctx := context.Background()
 			var conn connection.Connection
+			var quorums map[types.Action]uint64
 			if !txCfg.Offline {
 				var err error
 				conn, err = connection.Connect(ctx, npa.Network)
 				cobra.CheckErr(err)
-			}
 
-			var roleAddrs []accounts.RoleAddress
+				common.CheckForceErr(preflightInitOwners(ctx, conn, npa))
 
-			for i := 0; i < len(args); i += 2 {
-				roleAddrStr, roleStr := args[i], args[i+1]
-
-				// Resolve destination address.
-				roleAddr, err := common.ResolveLocalAccountOrAddress(npa.Network, roleAddrStr)
-				cobra.CheckErr(err)
-
-				role, err := types.RoleFromString(roleStr)
+				quorums, err = queryQuorums(ctx, conn, npa.ParaTime, client.RoundLatest)
 				cobra.CheckErr(err)
-
-				roleAddrs = append(roleAddrs, accounts.RoleAddress{
-					Addr: *roleAddr,
-					Role: role,
-				})
+				for _, err = range checkQuorumsReachable(initOwnersTeamSizes(members), quorums) {
+					common.CheckForceErr(err)
+				}
 			}
 
-			acc := common.LoadAccount(cfg, npa.AccountName)
-			// Prepare transactions for each owner-role pair.
-
-			var sigTx, meta interface{}
-			var err error 
+			printInitOwnersMatrix(members, quorums)
 
-			switch npa.ParaTime {
-			case nil:
-				err := "Invalid paratime configured!"
-				cobra.CheckErr(err)
+			acc := common.LoadAccount(cfg, npa.AccountName)
 
-			default:
-				// Prepare transaction.
-				tx := accounts.NewInitOwnersTx(nil, roleAddrs)
+			// Prepare transaction.
+			tx := accounts.NewInitOwnersTx(nil, roleAddresses(members))
 
-				sigTx, meta, err = common.SignParaTimeTransaction(ctx, npa, acc, conn, tx)
-				cobra.CheckErr(err)
-			}
+			sigTx, meta, err := common.SignParaTimeTransaction(ctx, npa, acc, conn, tx)
+			cobra.CheckErr(err)
 
 			common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
-
 		},
 	}
 
@@ -328,139 +305,54 @@ var (
 	managestProposalCmd = &cobra.Command{
 		Use:   "propose <proposal.json>",
 		Short: "Propose a new proposal with content from a JSON file",
-		Args:  cobra.ExactArgs(1),
+		Long: "Propose a new proposal with content from a JSON file, or use one of the subcommands to build the proposal from flags. " +
+			"With --export-review an offline review bundle is written instead of signing, which can later be signed " +
+			"with --from-review once the reviewer approved its content hash.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if proposeFromReview != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cfg := cliConfig.Global()
 			npa := common.GetNPASelection(cfg)
-			txCfg := common.GetTransactionConfig()
+
+			if npa.ParaTime == nil {
+				// GB: ignore other layers currently.
+			    cobra.CheckErr(fmt.Errorf("Invalid paratime configured!"))
+			}
+
+			if proposeFromReview != "" {
+				if proposeExportReview != "" {
+					cobra.CheckErr("--from-review and --export-review are mutually exclusive")
+				}
+				_, content := loadReviewBundle(npa)
+				submitProposal(npa, content)
+				return
+			}
 
 			// Read the JSON file.
 			jsonFile := args[0]
 			jsonData, err := ioutil.ReadFile(jsonFile)
 			cobra.CheckErr(err)
 
-		    var raw json.RawMessage
-		    tmp := struct {
-		        Action string          `json:"action"`
-		        Data   *json.RawMessage `json:"data"`
-		    }{Data: &raw}
+			action, proposalDataStr, err := parseProposal(jsonData)
+			cobra.CheckErr(err)
+			setMetaFromFile(cmd, proposalDataStr)
 
-		    err = json.Unmarshal(jsonData, &tmp)
+			proposalData, err := newProposalData(npa, action, proposalDataStr)
 			cobra.CheckErr(err)
 
-			if npa.Account == nil {
-				cobra.CheckErr("no accounts configured in your wallet")
+			content := &accounts.ProposalContent{
+				Action: action,
+				Data:   *proposalData,
 			}
-
-			acc := common.LoadAccount(cfg, npa.AccountName)
-
-			// When not in offline mode, connect to the given network endpoint.
-			ctx := context.Background()
-			var conn connection.Connection
-			if !txCfg.Offline {
-				var err error
-				conn, err = connection.Connect(ctx, npa.Network)
-				cobra.CheckErr(err)
+			if proposeExportReview != "" {
+				exportReviewBundle(npa, proposalDataStr, content)
+				return
 			}
-
-			var sigTx, meta interface{}
-			switch npa.ParaTime {
-			case nil:
-				// GB: ignore other layers currently.
-			    cobra.CheckErr(fmt.Errorf("Invalid paratime configured!"))
-
-			default:
-				action, err := types.ActionFromString(tmp.Action)
-				cobra.CheckErr(err)
-
-				// GB: take the input string to dataStr structure.
-		        var proposalDataStr *types.ProposalDataStr
-		        err = json.Unmarshal(raw, &proposalDataStr)
-				cobra.CheckErr(err)
-    			// fmt.Printf("gbtest: proposalDataStr is %s\n", proposalDataStr)
-
-				var proposalData types.ProposalData
-			    switch action {
-			    case types.Mint, types.Burn:
-			    	if proposalDataStr.Role != nil || 
-			    		proposalDataStr.MintQuorum != nil || proposalDataStr.BurnQuorum != nil || 
-			    		proposalDataStr.BlacklistQuorum != nil || proposalDataStr.ConfigQuorum != nil {
-			    			cobra.CheckErr(fmt.Errorf("invalid input for proposal!"))
-			    	}
-
-			        addr, err := common.ResolveLocalAccountOrAddress(npa.Network, *proposalDataStr.Address)
-					cobra.CheckErr(err)
-					amtBaseUnits, err := helpers.ParseParaTimeDenomination(npa.ParaTime, *proposalDataStr.Amount, types.NativeDenomination)
-					cobra.CheckErr(err)
-				    metadata, err := types.StringToMeta(proposalDataStr.Meta)
-				    cobra.CheckErr(err)
-
-					proposalData = types.ProposalData{
-						Address: addr,
-						Amount: amtBaseUnits,
-						Meta: metadata,
-					}
-
-			    case types.SetRoles:
-			    	if proposalDataStr.Amount != nil || 
-			    		proposalDataStr.MintQuorum != nil || proposalDataStr.BurnQuorum != nil || 
-			    		proposalDataStr.BlacklistQuorum != nil || proposalDataStr.ConfigQuorum != nil {
-			    			cobra.CheckErr(fmt.Errorf("invalid input for proposal!"))
-			    	}
-
-			        addr, err := common.ResolveLocalAccountOrAddress(npa.Network, *proposalDataStr.Address)
-					cobra.CheckErr(err)
-			        role, err := types.RoleFromString(*proposalDataStr.Role)
-					cobra.CheckErr(err)
-
-					proposalData = types.ProposalData{
-						Address: addr,
-						Role: &role,
-					}
-					
-			    case types.Whitelist, types.Blacklist:
-			    	if proposalDataStr.Role != nil || proposalDataStr.Amount != nil || 
-			    		proposalDataStr.MintQuorum != nil || proposalDataStr.BurnQuorum != nil || 
-			    		proposalDataStr.BlacklistQuorum != nil || proposalDataStr.ConfigQuorum != nil {
-			    			cobra.CheckErr(fmt.Errorf("invalid input for proposal!"))
-			    	}
-
-			        addr, err := common.ResolveLocalAccountOrAddress(npa.Network, *proposalDataStr.Address)
-					cobra.CheckErr(err)
-
-					proposalData = types.ProposalData{
-						Address: addr,
-					}
-
-			    case types.Config:
-			    	if proposalDataStr.Amount != nil || proposalDataStr.Address != nil {
-			    		cobra.CheckErr(fmt.Errorf("invalid input for proposal!"))
-			    	}
-
-					proposalData = types.ProposalData{
-						MintQuorum: proposalDataStr.MintQuorum,
-					    BurnQuorum: proposalDataStr.BurnQuorum,
-						WhitelistQuorum: proposalDataStr.WhitelistQuorum, 
-					    BlacklistQuorum: proposalDataStr.BlacklistQuorum,
-					    ConfigQuorum: proposalDataStr.ConfigQuorum,
-					}
-
-			    default:
-			    	cobra.CheckErr(fmt.Errorf("invalid action for proposal!"))
-			    }
-
-				// Prepare transaction.
-				proposal := &accounts.ProposalContent{
-					Action: action,
-					Data: proposalData,
-				}				
-				tx := accounts.NewProposeTx(nil, proposal)
-
-				sigTx, meta, err = common.SignParaTimeTransaction(ctx, npa, acc, conn, tx)
-				cobra.CheckErr(err)
-			}
-
-			common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
+			submitProposal(npa, content)
 		},
 	}
 
@@ -478,6 +370,17 @@ var (
 			if npa.Account == nil {
 				cobra.CheckErr("no accounts configured in your wallet")
 			}
+			if npa.ParaTime == nil {
+			    cobra.CheckErr(fmt.Errorf("Invalid paratime configured!"))
+			}
+
+			u64ID, err := strconv.ParseUint(proposalID, 10, 32)
+			cobra.CheckErr(err)
+			u32ID := uint32(u64ID)
+
+			lowercaseOption := strings.ToLower(option)
+			voteOp, err := types.StringToVote(lowercaseOption)
+			cobra.CheckErr(err)
 
 			// When not in offline mode, connect to the given network endpoint.
 			ctx := context.Background()
@@ -486,32 +389,21 @@ var (
 				var err error
 				conn, err = connection.Connect(ctx, npa.Network)
 				cobra.CheckErr(err)
+
+				// Check the signer's role and the proposal before unlocking the account.
+				common.CheckForceErr(preflightVote(ctx, conn, npa, u32ID))
 			}
 
 			acc := common.LoadAccount(cfg, npa.AccountName)
 
-			var sigTx, meta interface{}
-			switch npa.ParaTime {
-			case nil:
-			    cobra.CheckErr(fmt.Errorf("Invalid paratime configured!"))
-
-			default:
-				u64ID, err := strconv.ParseUint(proposalID, 10, 32)
-				u32ID := uint32(u64ID)
-
-    			lowercaseOption := strings.ToLower(option)
-    			voteOp, err := types.StringToVote(lowercaseOption)
-				cobra.CheckErr(err)
+			// Prepare transaction.
+			tx := accounts.NewVoteSTTx(nil, &accounts.VoteProposal{
+				ID:     u32ID,
+				Option: voteOp,
+			})
 
-				// Prepare transaction.
-				tx := accounts.NewVoteSTTx(nil, &accounts.VoteProposal{
-					ID:     u32ID,
-					Option: voteOp,
-				})
-
-				sigTx, meta, err = common.SignParaTimeTransaction(ctx, npa, acc, conn, tx)
-				cobra.CheckErr(err)
-			}
+			sigTx, meta, err := common.SignParaTimeTransaction(ctx, npa, acc, conn, tx)
+			cobra.CheckErr(err)
 
 			common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
 		},
@@ -523,17 +415,26 @@ var (
 
 func init() {
 	managestShowPropCmd.Flags().AddFlagSet(common.SelectorFlags)
-	managestShowPropCmd.Flags().AddFlagSet(common.HeightFlag)
+	managestShowPropCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestShowPropCmd.Flags().AddFlagSet(common.FormatFlag)
 	managestShowRolesCmd.Flags().AddFlagSet(common.SelectorFlags)
-	managestShowRolesCmd.Flags().AddFlagSet(common.HeightFlag)
+	managestShowRolesCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestShowRolesCmd.Flags().AddFlagSet(common.FormatFlag)
 	managestShowQuorumsCmd.Flags().AddFlagSet(common.SelectorFlags)
-	managestShowQuorumsCmd.Flags().AddFlagSet(common.HeightFlag)
+	managestShowQuorumsCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestShowQuorumsCmd.Flags().AddFlagSet(common.FormatFlag)
 
 
+	initOwnersFlags := flag.NewFlagSet("", flag.ContinueOnError)
+	initOwnersFlags.StringVar(&initOwnersFile, "from-file", "", "read the role teams from a TOML or YAML file")
 	managestInitOwnersCmd.Flags().AddFlagSet(common.SelectorFlags)
 	managestInitOwnersCmd.Flags().AddFlagSet(common.TransactionFlags)
 	managestInitOwnersCmd.Flags().AddFlagSet(common.ForceFlag)
+	managestInitOwnersCmd.Flags().AddFlagSet(initOwnersFlags)
 
+	managestProposalCmd.Flags().AddFlagSet(newMetaFileFlag())
+	managestProposalCmd.Flags().AddFlagSet(newExportReviewFlag())
+	managestProposalCmd.Flags().AddFlagSet(newFromReviewFlags())
 	managestProposalCmd.Flags().AddFlagSet(common.SelectorFlags)
 	managestProposalCmd.Flags().AddFlagSet(common.TransactionFlags)
 	managestProposalCmd.Flags().AddFlagSet(common.ForceFlag)
@@ -542,6 +443,7 @@ func init() {
 	managestVoteCmd.Flags().AddFlagSet(common.SelectorFlags)
 	managestVoteCmd.Flags().AddFlagSet(common.TransactionFlags)
 	managestVoteCmd.Flags().AddFlagSet(common.ForceFlag)
+	managestVoteCmd.Flags().AddFlagSet(newVoteScanFlags())
 
 
 
@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// proposalInfo is a decoded stablecoin management proposal.
+type proposalInfo struct {
+	ID         uint32            `json:"id"`
+	Submitter  types.Address     `json:"submitter"`
+	State      string            `json:"state"`
+	ActionName string            `json:"action"`
+	Content    map[string]string `json:"content"`
+	Results    map[string]uint64 `json:"results"`
+
+	Raw accounts.ProposalContent `json:"-"`
+}
+
+// fetchProposal queries the proposal with the given ID.
+//
+// Returns nil, if the proposal does not exist.
+func fetchProposal(ctx context.Context, conn connection.Connection, pt *config.ParaTime, round uint64, id uint32) (*proposalInfo, error) {
+	proposal, err := conn.Runtime(pt).Accounts.ProposalInfo(ctx, round, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query proposal %d: %w", id, err)
+	}
+	if proposal.Content.Action == types.NoAction {
+		return nil, nil
+	}
+
+	content, err := proposal.Content.String()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode proposal %d content: %w", id, err)
+	}
+
+	results := make(map[string]uint64)
+	for vote, count := range proposal.Results {
+		results[vote.String()] = uint64(count)
+	}
+
+	return &proposalInfo{
+		ID:         proposal.ID,
+		Submitter:  proposal.Submitter,
+		State:      proposal.State.String(),
+		ActionName: proposal.Content.Action.String(),
+		Content:    content,
+		Results:    results,
+		Raw:        proposal.Content,
+	}, nil
+}
+
+// voterRoleForAction returns the role whose members vote on proposals with the given action.
+func voterRoleForAction(action types.Action) (types.Role, error) {
+	switch action {
+	case types.SetRoles, types.Config:
+		return types.Admin, nil
+	default:
+		return types.RoleFromString(action.String() + "Voter")
+	}
+}
+
+// findProposalRound returns the first round at which the proposal with the given ID exists,
+// searching no further back than the last retained round.
+func findProposalRound(ctx context.Context, conn connection.Connection, pt *config.ParaTime, id uint32, round uint64) (uint64, error) {
+	lastRetained, err := conn.Runtime(pt).GetLastRetainedBlock(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query last retained runtime block: %w", err)
+	}
+
+	lo, hi := lastRetained.Header.Round, round
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		latestID, err := conn.Runtime(pt).Accounts.ProposalIDInfo(ctx, mid)
+		if err != nil {
+			return 0, fmt.Errorf("failed to query proposal ID at round %d: %w", mid, err)
+		}
+		if latestID >= id {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
+
+// findProposalCloseRound returns the first round at which the proposal with the given ID is no
+// longer open for voting, searching between the round the proposal was created at and the given
+// round at which the proposal must already be closed.
+func findProposalCloseRound(ctx context.Context, conn connection.Connection, pt *config.ParaTime, id uint32, round uint64) (uint64, error) {
+	lo, err := findProposalRound(ctx, conn, pt, id, round)
+	if err != nil {
+		return 0, err
+	}
+
+	hi := round
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		proposal, err := fetchProposal(ctx, conn, pt, mid, id)
+		if err != nil {
+			return 0, err
+		}
+		if proposal != nil && !proposal.isOpen() {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
+
+// proposalVote is a successful vote on a proposal.
+type proposalVote struct {
+	ProposalID uint32
+	Voter      types.Address
+	Option     string
+}
+
+// roundTransactions returns all successfully executed transactions of the given round.
+func roundTransactions(ctx context.Context, conn connection.Connection, pt *config.ParaTime, round uint64) ([]*types.Transaction, error) {
+	txs, err := conn.Runtime(pt).GetTransactionsWithResults(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions at round %d: %w", round, err)
+	}
+
+	var decoded []*types.Transaction
+	for _, txr := range txs {
+		if !txr.Result.IsSuccess() {
+			continue
+		}
+
+		var tx types.Transaction
+		if err = cbor.Unmarshal(txr.Tx.Body, &tx); err != nil {
+			continue
+		}
+		decoded = append(decoded, &tx)
+	}
+	return decoded, nil
+}
+
+// roundVotes returns all successful votes cast in the given round together with the signers of
+// successful encrypted transactions, which may contain votes that cannot be decoded.
+func roundVotes(ctx context.Context, conn connection.Connection, pt *config.ParaTime, round uint64) ([]*proposalVote, []types.Address, error) {
+	voteMethod := accounts.NewVoteSTTx(nil, &accounts.VoteProposal{}).Call.Method
+
+	txs, err := roundTransactions(ctx, conn, pt, round)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		votes     []*proposalVote
+		encrypted []types.Address
+	)
+	for _, tx := range txs {
+		if len(tx.AuthInfo.SignerInfo) == 0 {
+			continue
+		}
+		signer, err := tx.AuthInfo.SignerInfo[0].AddressSpec.Address()
+		if err != nil {
+			continue
+		}
+		if tx.Call.Format != types.CallFormatPlain {
+			encrypted = append(encrypted, signer)
+			continue
+		}
+		if tx.Call.Method != voteMethod {
+			continue
+		}
+
+		var vote accounts.VoteProposal
+		if err = cbor.Unmarshal(tx.Call.Body, &vote); err != nil {
+			continue
+		}
+		votes = append(votes, &proposalVote{
+			ProposalID: vote.ID,
+			Voter:      signer,
+			Option:     vote.Option.String(),
+		})
+	}
+	return votes, encrypted, nil
+}
+
+const defaultMaxProposals = 100
+
+var (
+	proposalSinceID      uint32
+	proposalMaxProposals uint32
+)
+
+// newProposalRangeFlags returns the flags controlling how many of the most recent proposals are
+// looked at.
+func newProposalRangeFlags() *flag.FlagSet {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.Uint32Var(&proposalSinceID, "since-id", 0, "do not look at proposals older than the given ID")
+	f.Uint32Var(&proposalMaxProposals, "max-proposals", defaultMaxProposals, "only look at the given number of most recent proposals unless --since-id is given (0 for all)")
+	return f
+}
+
+// proposalRangeStart returns the ID of the oldest proposal to look at as selected by the flags
+// of newProposalRangeFlags, given the ID of the latest proposal.
+func proposalRangeStart(cmd *cobra.Command, latestID uint32) uint32 {
+	if cmd.Flags().Changed("since-id") || proposalMaxProposals == 0 || latestID < proposalMaxProposals {
+		return proposalSinceID
+	}
+	return latestID - proposalMaxProposals + 1
+}
+
+const defaultVoteLookback = 1000
+
+var (
+	voteLookback uint64
+	voteFullScan bool
+)
+
+// newVoteScanFlags returns the flags controlling how far back votes on proposals are searched.
+func newVoteScanFlags() *flag.FlagSet {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.Uint64Var(&voteLookback, "vote-lookback", defaultVoteLookback, "maximum number of recent rounds searched for votes on a proposal")
+	f.BoolVar(&voteFullScan, "full-vote-scan", false, "search all rounds since a proposal was created for votes (slow)")
+	return f
+}
+
+// proposalVotes are the votes on a proposal found by scanProposalVotes.
+type proposalVotes struct {
+	// Votes are the chosen options keyed by voter address.
+	Votes map[types.Address]string
+	// Encrypted are the signers of encrypted transactions in the scanned rounds.
+	Encrypted map[types.Address]bool
+	// Complete is true if the votes of all voters counted in the proposal results were found.
+	Complete bool
+	// Covered is true if all rounds since the proposal was created were scanned.
+	Covered bool
+}
+
+// lookup returns the option the given address voted for or an empty string if it did not vote.
+// Returns false if it cannot be determined whether the address voted.
+func (pv *proposalVotes) lookup(addr types.Address) (string, bool) {
+	if option, ok := pv.Votes[addr]; ok {
+		return option, true
+	}
+	return "", pv.Complete || (pv.Covered && !pv.Encrypted[addr])
+}
+
+// hint returns a hint on how to make an incomplete vote scan conclusive.
+func (pv *proposalVotes) hint() string {
+	if pv.Covered {
+		return "some votes may be encrypted"
+	}
+	return "use --full-vote-scan to search all rounds since the proposal was created"
+}
+
+// scanProposalVotes scans the runtime transactions backwards from the given round for votes on
+// the given proposal. The scan stops as soon as all votes counted in the proposal results are
+// found and otherwise goes back at most --vote-lookback rounds unless --full-vote-scan is given.
+func scanProposalVotes(ctx context.Context, conn connection.Connection, pt *config.ParaTime, proposal *proposalInfo, round uint64) (*proposalVotes, error) {
+	pv := &proposalVotes{
+		Votes:     make(map[types.Address]string),
+		Encrypted: make(map[types.Address]bool),
+	}
+
+	var total uint64
+	for _, count := range proposal.Results {
+		total += count
+	}
+	if total == 0 {
+		pv.Complete, pv.Covered = true, true
+		return pv, nil
+	}
+
+	fromRound, err := findProposalRound(ctx, conn, pt, proposal.ID, round)
+	if err != nil {
+		return nil, err
+	}
+	pv.Covered = true
+	if !voteFullScan && voteLookback > 0 && round-fromRound >= voteLookback {
+		fromRound = round - voteLookback + 1
+		pv.Covered = false
+	}
+
+	for r := round; ; r-- {
+		votes, encrypted, err := roundVotes(ctx, conn, pt, r)
+		if err != nil {
+			return nil, err
+		}
+		for _, vote := range votes {
+			if _, seen := pv.Votes[vote.Voter]; vote.ProposalID == proposal.ID && !seen {
+				pv.Votes[vote.Voter] = vote.Option
+			}
+		}
+		for _, signer := range encrypted {
+			pv.Encrypted[signer] = true
+		}
+		if uint64(len(pv.Votes)) >= total {
+			pv.Complete = true
+			break
+		}
+		if r <= fromRound {
+			break
+		}
+	}
+	return pv, nil
+}
+
+// sortedKeys returns the keys of the given map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// voteCount returns the number of votes for the given option, ignoring case.
+func voteCount(results map[string]uint64, option string) uint64 {
+	for vote, count := range results {
+		if strings.EqualFold(vote, option) {
+			return count
+		}
+	}
+	return 0
+}
+
+// queryRoleTeams returns the members of all role teams.
+func queryRoleTeams(ctx context.Context, conn connection.Connection, pt *config.ParaTime, round uint64) (map[types.Role][]types.Address, error) {
+	teams := make(map[types.Role][]types.Address)
+	for role := types.Admin; role < types.User; role++ {
+		addrs, err := conn.Runtime(pt).Accounts.RolesTeam(ctx, round, role)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s team: %w", role, err)
+		}
+		teams[role] = addrs
+	}
+	return teams, nil
+}
+
+// queryQuorums returns the quorums in percent of all proposal actions.
+func queryQuorums(ctx context.Context, conn connection.Connection, pt *config.ParaTime, round uint64) (map[types.Action]uint64, error) {
+	quorums := make(map[types.Action]uint64)
+	for action := types.SetRoles; action <= types.Config; action++ {
+		quorum, err := conn.Runtime(pt).Accounts.Quorums(ctx, round, action)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s quorum: %w", action, err)
+		}
+		quorums[action] = uint64(quorum)
+	}
+	return quorums, nil
+}
+
+// findClosedProposal returns the first of the given proposals that was closed (executed or
+// rejected) in the given round, if any.
+func findClosedProposal(ctx context.Context, conn connection.Connection, pt *config.ParaTime, ids []uint32, round uint64) (*proposalInfo, error) {
+	for _, id := range ids {
+		after, err := fetchProposal(ctx, conn, pt, round, id)
+		if err != nil {
+			return nil, err
+		}
+		if after == nil || after.isOpen() {
+			continue
+		}
+		before, err := fetchProposal(ctx, conn, pt, round-1, id)
+		if err != nil {
+			return nil, err
+		}
+		if before == nil || before.State != after.State {
+			return after, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+	"github.com/oasisprotocol/cli/table"
+)
+
+var (
+	inboxAllAccounts bool
+
+	managestInboxCmd = &cobra.Command{
+		Use:   "inbox",
+		Short: "Show open proposals awaiting a vote from the selected account",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg := cliConfig.Global()
+			npa := common.GetNPASelection(cfg)
+
+			if npa.ParaTime == nil {
+				cobra.CheckErr("no runtime configured")
+			}
+
+			// Determine which accounts to check.
+			accountNames := []string{npa.AccountName}
+			if inboxAllAccounts {
+				accountNames = make([]string, 0, len(cfg.Wallet.All))
+				for name := range cfg.Wallet.All {
+					accountNames = append(accountNames, name)
+				}
+				sort.Strings(accountNames)
+			} else if npa.Account == nil {
+				cobra.CheckErr("no accounts configured in your wallet")
+			}
+
+			// Establish connection with the target network.
+			ctx := context.Background()
+			conn, err := connection.Connect(ctx, npa.Network)
+			cobra.CheckErr(err)
+
+			round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+			cobra.CheckErr(err)
+
+			// Query roles of all accounts.
+			roles := make(map[string]types.Role)
+			addrs := make(map[string]types.Address)
+			for _, name := range accountNames {
+				addr, err := helpers.ResolveAddress(npa.Network, cfg.Wallet.All[name].Address)
+				cobra.CheckErr(err)
+				role, err := conn.Runtime(npa.ParaTime).Accounts.Role(ctx, round, *addr)
+				cobra.CheckErr(err)
+
+				addrs[name] = *addr
+				roles[name] = role
+			}
+
+			latestID, err := conn.Runtime(npa.ParaTime).Accounts.ProposalIDInfo(ctx, round)
+			cobra.CheckErr(err)
+
+			// Only look at the most recent proposals unless told otherwise.
+			sinceID := int64(proposalRangeStart(cmd, latestID))
+
+			var (
+				output  [][]string
+				unknown bool
+			)
+			for id := int64(latestID); id >= sinceID; id-- {
+				proposal, err := fetchProposal(ctx, conn, npa.ParaTime, round, uint32(id))
+				cobra.CheckErr(err)
+				if proposal == nil || !proposal.isOpen() {
+					continue
+				}
+
+				voterRole, err := voterRoleForAction(proposal.Raw.Action)
+				cobra.CheckErr(err)
+
+				// Only look at the votes if any of the accounts is eligible to vote.
+				var eligible []string
+				for _, name := range accountNames {
+					if roles[name] == voterRole {
+						eligible = append(eligible, name)
+					}
+				}
+				if len(eligible) == 0 {
+					continue
+				}
+
+				createdRound, err := findProposalRound(ctx, conn, npa.ParaTime, proposal.ID, round)
+				cobra.CheckErr(err)
+				votes, err := scanProposalVotes(ctx, conn, npa.ParaTime, proposal, round)
+				cobra.CheckErr(err)
+
+				for _, name := range eligible {
+					status := "not voted"
+					switch option, known := votes.lookup(addrs[name]); {
+					case option != "":
+						continue
+					case !known:
+						status = "unknown"
+						unknown = true
+					}
+					output = append(output, []string{
+						name,
+						fmt.Sprintf("%d", proposal.ID),
+						proposal.ActionName,
+						formatProposalAmount(npa.ParaTime, proposal),
+						formatProposalTarget(cfg, proposal),
+						fmt.Sprintf("%d", round-createdRound),
+						status,
+					})
+				}
+			}
+
+			if len(output) == 0 {
+				fmt.Println("No proposals are awaiting your vote.")
+				return
+			}
+
+			table := table.New()
+			table.SetHeader([]string{"Account", "ID", "Action", "Amount", "Target", "Age (rounds)", "Status"})
+			table.AppendBulk(output)
+			table.Render()
+
+			if unknown {
+				fmt.Println("\nSome votes could not be determined, as they are encrypted or older than --vote-lookback rounds (see --full-vote-scan).")
+			}
+		},
+	}
+)
+
+// formatProposalAmount returns the formatted amount of a Mint or Burn proposal.
+func formatProposalAmount(pt *config.ParaTime, proposal *proposalInfo) string {
+	if proposal.Raw.Data.Amount == nil {
+		return ""
+	}
+	return helpers.FormatParaTimeDenomination(pt, *proposal.Raw.Data.Amount)
+}
+
+// formatProposalTarget returns the address the proposal applies to including its local name, or
+// the proposal content if the proposal does not target an address.
+func formatProposalTarget(cfg *cliConfig.Config, proposal *proposalInfo) string {
+	addr := proposal.Raw.Data.Address
+	if addr == nil {
+		return formatKeyValues(proposal.Content)
+	}
+	if name := common.FindAccountName(cfg, addr.String()); name != "" {
+		return fmt.Sprintf("%s (%s)", addr, name)
+	}
+	return addr.String()
+}
+
+func init() {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.BoolVar(&inboxAllAccounts, "all-accounts", false, "check all accounts in the wallet")
+
+	managestInboxCmd.Flags().AddFlagSet(common.SelectorFlags)
+	managestInboxCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestInboxCmd.Flags().AddFlagSet(newProposalRangeFlags())
+	managestInboxCmd.Flags().AddFlagSet(newVoteScanFlags())
+	managestInboxCmd.Flags().AddFlagSet(f)
+
+	managestCmd.AddCommand(managestInboxCmd)
+}
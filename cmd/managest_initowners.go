@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	"github.com/oasisprotocol/cli/table"
+)
+
+// initOwnersMember is a role team member from a roles file.
+type initOwnersMember struct {
+	// Name is the address or the wallet/address book name as given in the roles file.
+	Name    string
+	Address types.Address
+	Role    types.Role
+}
+
+// roleFromName returns the role with the given name, ignoring case.
+func roleFromName(name string) (types.Role, error) {
+	for role := types.Admin; role < types.User; role++ {
+		if strings.EqualFold(role.String(), name) {
+			return role, nil
+		}
+	}
+	return types.User, fmt.Errorf("unknown role '%s'", name)
+}
+
+// initOwnersRolesExample is the example roles file shown in the help of initowners --from-file.
+// Each address may only appear in one role team.
+const initOwnersRolesExample = `Admin = ["alice", "bob"]
+MintProposer = ["carol"]
+MintVoter = ["dave", "oasis1qr5a0vnc5vnx6ae0c6j25ncq9zrux4g2pgxw9s5l"]
+`
+
+// loadRolesFile loads the initial role teams from a TOML or YAML file which maps role names to
+// lists of wallet account names, address book names or addresses, e.g.:
+//
+//	Admin = ["alice", "bob"]
+//	MintProposer = ["carol"]
+//	MintVoter = ["dave", "oasis1qr5a0vnc5vnx6ae0c6j25ncq9zrux4g2pgxw9s5l"]
+//
+// All problems found in the file are returned.
+func loadRolesFile(npa *common.NPASelection, path string) ([]*initOwnersMember, []error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, []error{fmt.Errorf("failed to read roles file: %w", err)}
+	}
+	var teams map[string][]string
+	if err := v.Unmarshal(&teams); err != nil {
+		return nil, []error{fmt.Errorf("malformed roles file: %w", err)}
+	}
+
+	var errs []error
+	byRole := make(map[types.Role][]string)
+	for name, members := range teams {
+		role, err := roleFromName(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		byRole[role] = members
+	}
+
+	var members []*initOwnersMember
+	seen := make(map[types.Address]*initOwnersMember)
+	for role := types.Admin; role < types.User; role++ {
+		for _, name := range byRole[role] {
+			addr, err := common.ResolveLocalAccountOrAddress(npa.Network, name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: cannot resolve '%s': %w", role, name, err))
+				continue
+			}
+			if prev, ok := seen[*addr]; ok {
+				errs = append(errs, fmt.Errorf("%s: '%s' is already listed as '%s' in %s (an address can only have one role)", role, name, prev.Name, prev.Role))
+				continue
+			}
+
+			member := &initOwnersMember{Name: name, Address: *addr, Role: role}
+			seen[*addr] = member
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 && len(errs) == 0 {
+		errs = append(errs, fmt.Errorf("roles file does not list any members"))
+	}
+	if len(byRole[types.Admin]) == 0 {
+		errs = append(errs, fmt.Errorf("no %s configured, roles could never be changed", types.Admin))
+	}
+	return members, errs
+}
+
+// parseInitOwnersArgs parses positional address and role pairs.
+func parseInitOwnersArgs(npa *common.NPASelection, args []string) ([]*initOwnersMember, error) {
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("expected address and role pairs, but got an odd number of arguments")
+	}
+
+	var members []*initOwnersMember
+	for i := 0; i < len(args); i += 2 {
+		addr, err := common.ResolveLocalAccountOrAddress(npa.Network, args[i])
+		if err != nil {
+			return nil, err
+		}
+		role, err := types.RoleFromString(args[i+1])
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, &initOwnersMember{Name: args[i], Address: *addr, Role: role})
+	}
+	return members, nil
+}
+
+// initOwnersTeamSizes returns the number of members of each role team.
+func initOwnersTeamSizes(members []*initOwnersMember) map[types.Role]uint64 {
+	sizes := make(map[types.Role]uint64)
+	for _, member := range members {
+		sizes[member.Role]++
+	}
+	return sizes
+}
+
+// checkQuorumsReachable checks that the proposals of each action can be submitted and can reach
+// the given quorums with the given team sizes. A quorum of 0% is reported as well, since such
+// proposals pass without any votes.
+func checkQuorumsReachable(sizes map[types.Role]uint64, quorums map[types.Action]uint64) []error {
+	var errs []error
+	for action := types.SetRoles; action <= types.Config; action++ {
+		proposer, err := proposerRoleForAction(action)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		voter, err := voterRoleForAction(action)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if sizes[proposer] == 0 {
+			errs = append(errs, fmt.Errorf("%s proposals cannot be submitted: %s team is empty", action, proposer))
+		}
+		tally := proposalTally{Quorum: quorums[action], TeamSize: sizes[voter]}
+		switch {
+		case tally.NoVotesRequired():
+			errs = append(errs, fmt.Errorf("%s quorum is 0%%: %s proposals pass without any votes", action, action))
+		case tally.TeamSize == 0:
+			errs = append(errs, fmt.Errorf("%s proposals cannot pass: %s team is empty", action, voter))
+		case !tally.Reachable():
+			errs = append(errs, fmt.Errorf("%s quorum of %d%% cannot be reached: %s team has %d member(s)", action, quorums[action], voter, tally.TeamSize))
+		}
+	}
+	return errs
+}
+
+// printInitOwnersMatrix prints the role teams to be initialized. Voter teams include the number of
+// votes required by the given quorums, if known.
+func printInitOwnersMatrix(members []*initOwnersMember, quorums map[types.Action]uint64) {
+	sizes := initOwnersTeamSizes(members)
+
+	required := make(map[types.Role][]string)
+	for action := types.SetRoles; action <= types.Config; action++ {
+		quorum, ok := quorums[action]
+		if !ok {
+			continue
+		}
+		voter, err := voterRoleForAction(action)
+		if err != nil {
+			continue
+		}
+		tally := proposalTally{Quorum: quorum, TeamSize: sizes[voter]}
+		if tally.NoVotesRequired() {
+			required[voter] = append(required[voter], fmt.Sprintf("%s: 0%% = no votes required", action))
+			continue
+		}
+		required[voter] = append(required[voter], fmt.Sprintf("%s: %d%% = %d of %d", action, quorum, tally.Required(), tally.TeamSize))
+	}
+
+	var output [][]string
+	for role := types.Admin; role < types.User; role++ {
+		var names []string
+		for _, member := range members {
+			if member.Role != role {
+				continue
+			}
+			if member.Name != member.Address.String() {
+				names = append(names, fmt.Sprintf("%s (%s)", member.Address, member.Name))
+			} else {
+				names = append(names, member.Address.String())
+			}
+		}
+		output = append(output, []string{
+			role.String(),
+			fmt.Sprintf("%d", sizes[role]),
+			strings.Join(names, "\n"),
+			strings.Join(required[role], "\n"),
+		})
+	}
+
+	table := table.New()
+	table.SetHeader([]string{"Role", "Size", "Members", "Votes required"})
+	table.AppendBulk(output)
+	table.Render()
+}
+
+// roleAddresses converts role team members into the initowners transaction body.
+func roleAddresses(members []*initOwnersMember) []accounts.RoleAddress {
+	roleAddrs := make([]accounts.RoleAddress, 0, len(members))
+	for _, member := range members {
+		roleAddrs = append(roleAddrs, accounts.RoleAddress{
+			Addr: member.Address,
+			Role: member.Role,
+		})
+	}
+	return roleAddrs
+}
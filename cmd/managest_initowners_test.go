@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+func TestLoadRolesFileExample(t *testing.T) {
+	require := require.New(t)
+
+	if _, err := roleFromName("Admin"); err != nil {
+		t.Skip("role names are not available")
+	}
+
+	ab := &cliConfig.Global().AddressBook
+	defer func(all map[string]*cliConfig.AddressBookEntry) { ab.All = all }(ab.All)
+	ab.All = make(map[string]*cliConfig.AddressBookEntry)
+	for _, name := range []string{"alice", "bob", "carol", "dave"} {
+		ab.All[name] = &cliConfig.AddressBookEntry{Address: types.NewAddressForModule("test", []byte(name)).String()}
+	}
+
+	path := filepath.Join(t.TempDir(), "roles.toml")
+	require.NoError(os.WriteFile(path, []byte(initOwnersRolesExample), 0o600))
+
+	members, errs := loadRolesFile(&common.NPASelection{Network: &config.Network{}}, path)
+	require.Empty(errs)
+	require.Len(members, 5)
+
+	sizes := initOwnersTeamSizes(members)
+	require.EqualValues(2, sizes[types.Admin])
+	require.EqualValues(1, sizes[types.MintProposer])
+	require.EqualValues(2, sizes[types.MintVoter])
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+	"github.com/oasisprotocol/cli/table"
+)
+
+var (
+	listProposalsState     string
+	listProposalsAction    string
+	listProposalsSubmitter string
+	listProposalsSinceID   uint32
+	listProposalsLimit     uint32
+
+	managestListProposalsCmd = &cobra.Command{
+		Use:   "list-proposals",
+		Short: "List proposals, newest first, optionally filtered by state, action or submitter",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg := cliConfig.Global()
+			npa := common.GetNPASelection(cfg)
+			format := common.GetOutputFormat()
+
+			if npa.ParaTime == nil {
+				cobra.CheckErr("no runtime configured")
+			}
+
+			// Parse filters early so that typos are reported before any queries are made.
+			var action *types.Action
+			if listProposalsAction != "" {
+				a, err := types.ActionFromString(listProposalsAction)
+				cobra.CheckErr(err)
+				action = &a
+			}
+			var submitter *types.Address
+			if listProposalsSubmitter != "" {
+				var err error
+				submitter, err = common.ResolveLocalAccountOrAddress(npa.Network, listProposalsSubmitter)
+				cobra.CheckErr(err)
+			}
+
+			// Establish connection with the target network.
+			ctx := context.Background()
+			conn, err := connection.Connect(ctx, npa.Network)
+			cobra.CheckErr(err)
+
+			round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+			cobra.CheckErr(err)
+
+			latestID, err := conn.Runtime(npa.ParaTime).Accounts.ProposalIDInfo(ctx, round)
+			cobra.CheckErr(err)
+
+			// Walk proposals back from the latest one.
+			proposals := []*proposalInfo{}
+			for id := int64(latestID); id >= int64(listProposalsSinceID); id-- {
+				if listProposalsLimit > 0 && uint32(len(proposals)) >= listProposalsLimit {
+					break
+				}
+
+				proposal, err := fetchProposal(ctx, conn, npa.ParaTime, round, uint32(id))
+				cobra.CheckErr(err)
+				if proposal == nil {
+					continue
+				}
+
+				if listProposalsState != "" && !strings.EqualFold(proposal.State, listProposalsState) {
+					continue
+				}
+				if action != nil && proposal.Raw.Action != *action {
+					continue
+				}
+				if submitter != nil && !proposal.Submitter.Equal(*submitter) {
+					continue
+				}
+				proposals = append(proposals, proposal)
+			}
+
+			switch format {
+			case common.FormatJSON, common.FormatYAML:
+				common.PrintStructured(format, proposals)
+			default:
+				table := table.New()
+				table.SetHeader([]string{"ID", "Action", "State", "Submitter", "Content", "Results"})
+
+				var output [][]string
+				for _, p := range proposals {
+					output = append(output, []string{
+						fmt.Sprintf("%d", p.ID),
+						p.ActionName,
+						p.State,
+						p.Submitter.String(),
+						formatKeyValues(p.Content),
+						formatResults(p.Results),
+					})
+				}
+
+				table.AppendBulk(output)
+				table.Render()
+			}
+		},
+	}
+)
+
+// formatKeyValues formats the given map as a sorted, comma-separated list of key=value pairs.
+func formatKeyValues(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// formatResults formats the given vote results as a sorted, comma-separated list of vote=count pairs.
+func formatResults(results map[string]uint64) string {
+	pairs := make([]string, 0, len(results))
+	for vote, count := range results {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", vote, count))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+func init() {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.StringVar(&listProposalsState, "state", "", "only show proposals in the given state")
+	f.StringVar(&listProposalsAction, "action", "", "only show proposals with the given action (e.g. Mint, Burn, SetRoles)")
+	f.StringVar(&listProposalsSubmitter, "submitter", "", "only show proposals submitted by the given account or address")
+	f.Uint32Var(&listProposalsSinceID, "since-id", 0, "do not look at proposals older than the given ID")
+	f.Uint32Var(&listProposalsLimit, "limit", 0, "maximum number of proposals to show (0 for no limit)")
+
+	managestListProposalsCmd.Flags().AddFlagSet(common.SelectorFlags)
+	managestListProposalsCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestListProposalsCmd.Flags().AddFlagSet(common.FormatFlag)
+	managestListProposalsCmd.Flags().AddFlagSet(f)
+
+	managestCmd.AddCommand(managestListProposalsCmd)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+// metaDigestPrefix is the prefix of proposal meta referencing a document by its digest. Such meta
+// has the form "sha512-256:<hex digest> <file name>".
+const metaDigestPrefix = "sha512-256:"
+
+// hashMetaFile returns the SHA-512/256 digest of the given file.
+func hashMetaFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha512.New512_256()
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, fmt.Errorf("failed to hash '%s': %w", path, err)
+	}
+	return h.Sum(nil), nil
+}
+
+// formatMetaDigest returns the proposal meta referencing a document with the given digest and
+// file name.
+func formatMetaDigest(digest []byte, name string) string {
+	return fmt.Sprintf("%s%s %s", metaDigestPrefix, hex.EncodeToString(digest), name)
+}
+
+// parseMetaDigest parses proposal meta referencing a document.
+func parseMetaDigest(meta string) ([]byte, string, error) {
+	if !strings.HasPrefix(meta, metaDigestPrefix) {
+		return nil, "", fmt.Errorf("proposal meta does not reference a document digest")
+	}
+	digestHex, name, _ := strings.Cut(strings.TrimPrefix(meta, metaDigestPrefix), " ")
+	digest, err := hex.DecodeString(digestHex)
+	if err != nil || len(digest) != sha512.Size256 {
+		return nil, "", fmt.Errorf("malformed document digest '%s'", digestHex)
+	}
+	return digest, name, nil
+}
+
+// newMetaFileFlag returns the flag for setting the proposal meta from a supporting document.
+func newMetaFileFlag() *flag.FlagSet {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.StringVar(&proposeMetaFile, "meta-file", "", "set proposal meta to the SHA-512/256 digest and name of the given document")
+	return f
+}
+
+// setMetaFromFile sets the proposal meta from the document given via --meta-file, if any.
+func setMetaFromFile(cmd *cobra.Command, dataStr *types.ProposalDataStr) {
+	if !cmd.Flags().Changed("meta-file") {
+		return
+	}
+	if dataStr.Meta != nil {
+		cobra.CheckErr("proposal meta is given both directly and via --meta-file")
+	}
+
+	digest, err := hashMetaFile(proposeMetaFile)
+	cobra.CheckErr(err)
+	meta := formatMetaDigest(digest, filepath.Base(proposeMetaFile))
+	dataStr.Meta = &meta
+}
+
+var managestVerifyMetaCmd = &cobra.Command{
+	Use:   "verify-meta <proposal-id> <file>",
+	Short: "Verify that a document is the one referenced by the proposal meta",
+	Long: "Hash the given file with SHA-512/256 and compare the digest with the document digest stored in the " +
+		"meta of the given proposal (see --meta-file of the propose commands).",
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+
+		if npa.ParaTime == nil {
+			cobra.CheckErr("no runtime configured")
+		}
+
+		id, err := strconv.ParseUint(args[0], 10, 32)
+		cobra.CheckErr(err)
+		digest, err := hashMetaFile(args[1])
+		cobra.CheckErr(err)
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+		cobra.CheckErr(err)
+		proposal, err := fetchProposal(ctx, conn, npa.ParaTime, round, uint32(id))
+		cobra.CheckErr(err)
+		if proposal == nil {
+			cobra.CheckErr(fmt.Errorf("proposal %d does not exist", id))
+		}
+
+		expected, name, err := parseMetaDigest(proposal.Content[proposalFieldMeta])
+		cobra.CheckErr(err)
+
+		fmt.Printf("Proposal %d references: %s (%s)\n", id, hex.EncodeToString(expected), name)
+		fmt.Printf("File digest:            %s (%s)\n", hex.EncodeToString(digest), filepath.Base(args[1]))
+		if !bytes.Equal(digest, expected) {
+			cobra.CheckErr(fmt.Errorf("document does not match proposal %d", id))
+		}
+		fmt.Printf("Document matches proposal %d.\n", id)
+	},
+}
+
+func init() {
+	managestVerifyMetaCmd.Flags().AddFlagSet(common.SelectorNPFlags)
+	managestVerifyMetaCmd.Flags().AddFlagSet(common.RoundFlag)
+
+	managestCmd.AddCommand(managestVerifyMetaCmd)
+}
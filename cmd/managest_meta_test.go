@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"crypto/sha512"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetaDigest(t *testing.T) {
+	require := require.New(t)
+
+	digest := sha512.Sum512_256([]byte("bank confirmation"))
+	meta := formatMetaDigest(digest[:], "confirmation 2023-01.pdf")
+
+	parsed, name, err := parseMetaDigest(meta)
+	require.NoError(err)
+	require.Equal(digest[:], parsed)
+	require.Equal("confirmation 2023-01.pdf", name)
+
+	_, _, err = parseMetaDigest("free text")
+	require.Error(err, "meta without digest")
+	_, _, err = parseMetaDigest(metaDigestPrefix + "abcd file.pdf")
+	require.Error(err, "short digest")
+}
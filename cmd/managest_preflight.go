@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+)
+
+const (
+	// proposalStateActive is the state of proposals which are still open for voting.
+	proposalStateActive = "active"
+	// proposalStateExecuted is the state of proposals which reached the quorum and were executed.
+	proposalStateExecuted = "executed"
+	// proposalStateRejected is the state of proposals which were voted down.
+	proposalStateRejected = "rejected"
+)
+
+// isOpen returns true, if the proposal is still open for voting.
+func (p *proposalInfo) isOpen() bool {
+	return strings.EqualFold(p.State, proposalStateActive)
+}
+
+// isExecuted returns true, if the proposal has been executed.
+func (p *proposalInfo) isExecuted() bool {
+	return strings.EqualFold(p.State, proposalStateExecuted)
+}
+
+// isRejected returns true, if the proposal has been rejected.
+func (p *proposalInfo) isRejected() bool {
+	return strings.EqualFold(p.State, proposalStateRejected)
+}
+
+// proposerRoleForAction returns the role whose members may submit proposals with the given action.
+func proposerRoleForAction(action types.Action) (types.Role, error) {
+	switch action {
+	case types.SetRoles, types.Config:
+		return types.Admin, nil
+	default:
+		return types.RoleFromString(action.String() + "Proposer")
+	}
+}
+
+// signerAddress resolves the address of the selected account without unlocking it.
+func signerAddress(npa *common.NPASelection) (*types.Address, error) {
+	return helpers.ResolveAddress(npa.Network, npa.Account.Address)
+}
+
+// preflightPropose checks that the selected account may submit a proposal with the given action.
+func preflightPropose(ctx context.Context, conn connection.Connection, npa *common.NPASelection, action types.Action) error {
+	addr, err := signerAddress(npa)
+	if err != nil {
+		return err
+	}
+
+	required, err := proposerRoleForAction(action)
+	if err != nil {
+		return err
+	}
+	role, err := conn.Runtime(npa.ParaTime).Accounts.Role(ctx, client.RoundLatest, *addr)
+	if err != nil {
+		return fmt.Errorf("failed to query role of %s: %w", addr, err)
+	}
+	if role != required {
+		return fmt.Errorf("account '%s' has role %s, but proposing %s requires role %s", npa.AccountName, role, action, required)
+	}
+	return nil
+}
+
+// preflightVote checks that the selected account may vote on the given proposal and has not
+// voted on it yet.
+func preflightVote(ctx context.Context, conn connection.Connection, npa *common.NPASelection, proposalID uint32) error {
+	addr, err := signerAddress(npa)
+	if err != nil {
+		return err
+	}
+	return preflightVoteAs(ctx, conn, npa, npa.AccountName, *addr, proposalID, nil)
+}
+
+// voteScanCache caches the votes found on proposals keyed by proposal ID, so that each proposal is
+// only scanned once when checking multiple votes.
+type voteScanCache map[uint32]*proposalVotes
+
+// preflightVoteAs checks that the given account may vote on the given proposal and has not voted
+// on it yet. The given cache may be nil.
+func preflightVoteAs(
+	ctx context.Context,
+	conn connection.Connection,
+	npa *common.NPASelection,
+	accountName string,
+	addr types.Address,
+	proposalID uint32,
+	cache voteScanCache,
+) error {
+	round, err := common.GetLatestRound(ctx, conn, npa.ParaTime)
+	if err != nil {
+		return err
+	}
+
+	proposal, err := fetchProposal(ctx, conn, npa.ParaTime, round, proposalID)
+	if err != nil {
+		return err
+	}
+	if proposal == nil {
+		return fmt.Errorf("proposal %d does not exist", proposalID)
+	}
+	if !proposal.isOpen() {
+		return fmt.Errorf("proposal %d is no longer open for voting (state: %s)", proposalID, proposal.State)
+	}
+
+	required, err := voterRoleForAction(proposal.Raw.Action)
+	if err != nil {
+		return err
+	}
+	role, err := conn.Runtime(npa.ParaTime).Accounts.Role(ctx, round, addr)
+	if err != nil {
+		return fmt.Errorf("failed to query role of %s: %w", addr, err)
+	}
+	if role != required {
+		return fmt.Errorf("account '%s' has role %s, but voting on %s proposals requires role %s", accountName, role, proposal.ActionName, required)
+	}
+
+	votes := cache[proposalID]
+	if votes == nil {
+		if votes, err = scanProposalVotes(ctx, conn, npa.ParaTime, proposal, round); err != nil {
+			return err
+		}
+		if cache != nil {
+			cache[proposalID] = votes
+		}
+	}
+	option, known := votes.lookup(addr)
+	switch {
+	case option != "":
+		return fmt.Errorf("account '%s' has already voted %s on proposal %d", accountName, option, proposalID)
+	case !known && votes.Encrypted[addr]:
+		return fmt.Errorf("cannot tell whether account '%s' has already voted on proposal %d, as it sent encrypted transactions", accountName, proposalID)
+	case !known:
+		fmt.Fprintf(os.Stderr, "Warning: cannot tell whether account '%s' has already voted on proposal %d (%s)\n", accountName, proposalID, votes.hint())
+	}
+	return nil
+}
+
+// preflightInitOwners checks that the initial owners have not been configured yet.
+func preflightInitOwners(ctx context.Context, conn connection.Connection, npa *common.NPASelection) error {
+	admins, err := conn.Runtime(npa.ParaTime).Accounts.RolesTeam(ctx, client.RoundLatest, types.Admin)
+	if err != nil {
+		return fmt.Errorf("failed to query %s team: %w", types.Admin, err)
+	}
+	if len(admins) > 0 {
+		return fmt.Errorf("owners have already been initialized (%d %s account(s) configured)", len(admins), types.Admin)
+	}
+	return nil
+}
+
+// preflightTransferStable checks that neither the sender nor the recipient of a stablecoin
+// transfer is blacklisted.
+func preflightTransferStable(ctx context.Context, conn connection.Connection, npa *common.NPASelection, to types.Address) error {
+	from, err := signerAddress(npa)
+	if err != nil {
+		return err
+	}
+
+	round, err := common.GetLatestRound(ctx, conn, npa.ParaTime)
+	if err != nil {
+		return err
+	}
+	lists, err := queryStablecoinLists(ctx, conn, npa, round, true)
+	if err != nil {
+		return err
+	}
+	for _, party := range []struct {
+		kind string
+		addr types.Address
+	}{
+		{"sender", *from},
+		{"recipient", to},
+	} {
+		if listing, ok := lists.Blacklist[party.addr]; ok {
+			return fmt.Errorf("%s %s is blacklisted since round %d (proposal %d)", party.kind, party.addr, listing.Round, listing.ProposalID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,523 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+// Names of the proposal data fields as used in proposal JSON files.
+const (
+	proposalFieldAddress         = "address"
+	proposalFieldAmount          = "amount"
+	proposalFieldMeta            = "meta"
+	proposalFieldRole            = "role"
+	proposalFieldMintQuorum      = "mint_quorum"
+	proposalFieldBurnQuorum      = "burn_quorum"
+	proposalFieldWhitelistQuorum = "whitelist_quorum"
+	proposalFieldBlacklistQuorum = "blacklist_quorum"
+	proposalFieldConfigQuorum    = "config_quorum"
+)
+
+// Valid range of quorums in percent.
+const (
+	minQuorum = 1
+	maxQuorum = 100
+)
+
+// proposalQuorumFields are the fields of a Config proposal.
+var proposalQuorumFields = []string{
+	proposalFieldMintQuorum,
+	proposalFieldBurnQuorum,
+	proposalFieldWhitelistQuorum,
+	proposalFieldBlacklistQuorum,
+	proposalFieldConfigQuorum,
+}
+
+// proposalFieldNames are the names of all proposal data fields.
+var proposalFieldNames = append([]string{
+	proposalFieldAddress,
+	proposalFieldAmount,
+	proposalFieldMeta,
+	proposalFieldRole,
+}, proposalQuorumFields...)
+
+// proposalAmountPattern is the format of proposal amounts in display units. Zero amounts are
+// rejected.
+var proposalAmountPattern = regexp.MustCompile(`^([0-9]*[1-9][0-9]*(\.[0-9]+)?|[0-9]+\.[0-9]*[1-9][0-9]*)$`)
+
+// proposalRoleNames returns the names of all roles that can be assigned by SetRoles proposals.
+func proposalRoleNames() []string {
+	var roles []string
+	for role := types.Admin; role < types.User; role++ {
+		roles = append(roles, role.String())
+	}
+	return roles
+}
+
+// proposalFieldSpec describes the values of a proposal data field. Both the JSON schema of
+// proposal files (see proposalSchema) and the checks of proposalFieldErrors are derived from it.
+type proposalFieldSpec struct {
+	description string
+	// pattern is the format of string values, if restricted.
+	pattern *regexp.Regexp
+	// patternHint describes the pattern in error messages.
+	patternHint string
+	// enum returns the allowed string values, if restricted.
+	enum func() []string
+	// quorum marks integer fields with values in [minQuorum, maxQuorum].
+	quorum bool
+}
+
+var quorumFieldSpec = &proposalFieldSpec{description: "Quorum in percent of the voter team.", quorum: true}
+
+// proposalFieldSpecs are the specifications of all proposal data fields.
+var proposalFieldSpecs = map[string]*proposalFieldSpec{
+	proposalFieldAddress: {description: "Wallet account name, address book name, native or Ethereum address."},
+	proposalFieldAmount: {
+		description: "Positive amount of stable tokens in display units of the runtime denomination.",
+		pattern:     proposalAmountPattern,
+		patternHint: "a positive decimal amount in display units",
+	},
+	proposalFieldMeta:            {description: "Free-form meta information, e.g. a reference to the supporting document."},
+	proposalFieldRole:            {enum: proposalRoleNames},
+	proposalFieldMintQuorum:      quorumFieldSpec,
+	proposalFieldBurnQuorum:      quorumFieldSpec,
+	proposalFieldWhitelistQuorum: quorumFieldSpec,
+	proposalFieldBlacklistQuorum: quorumFieldSpec,
+	proposalFieldConfigQuorum:    quorumFieldSpec,
+}
+
+// check returns the problem with the given value of the given field, if any.
+func (spec *proposalFieldSpec) check(field string, value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		if spec.pattern != nil && !spec.pattern.MatchString(v) {
+			return fmt.Errorf("field '%s': '%s' is not %s", field, v, spec.patternHint)
+		}
+		if spec.enum != nil {
+			allowed := spec.enum()
+			for _, a := range allowed {
+				if v == a {
+					return nil
+				}
+			}
+			return fmt.Errorf("field '%s': '%s' is not one of [%s]", field, v, strings.Join(allowed, ", "))
+		}
+	case uint64:
+		if spec.quorum && (v < minQuorum || v > maxQuorum) {
+			return fmt.Errorf("field '%s': quorum %d is out of range [%d, %d]", field, v, minQuorum, maxQuorum)
+		}
+	}
+	return nil
+}
+
+// proposalFieldRules are the required and optional proposal data fields for each action and the
+// minimum number of fields that must be set. Fields not listed for an action are forbidden.
+var proposalFieldRules = map[types.Action]struct {
+	required  []string
+	optional  []string
+	minFields int
+}{
+	types.Mint:      {required: []string{proposalFieldAddress, proposalFieldAmount}, optional: []string{proposalFieldMeta}},
+	types.Burn:      {required: []string{proposalFieldAddress, proposalFieldAmount}, optional: []string{proposalFieldMeta}},
+	types.SetRoles:  {required: []string{proposalFieldAddress, proposalFieldRole}},
+	types.Whitelist: {required: []string{proposalFieldAddress}},
+	types.Blacklist: {required: []string{proposalFieldAddress}},
+	types.Config:    {optional: proposalQuorumFields, minFields: 1},
+}
+
+// proposalFieldValues returns the values of all fields set in the given proposal data. Strings
+// are returned as string and quorums as uint64.
+func proposalFieldValues(dataStr *types.ProposalDataStr) map[string]interface{} {
+	values := make(map[string]interface{})
+	for field, v := range map[string]*string{
+		proposalFieldAddress: dataStr.Address,
+		proposalFieldAmount:  dataStr.Amount,
+		proposalFieldMeta:    dataStr.Meta,
+		proposalFieldRole:    dataStr.Role,
+	} {
+		if v != nil {
+			values[field] = *v
+		}
+	}
+	for field, v := range map[string]*uint8{
+		proposalFieldMintQuorum:      dataStr.MintQuorum,
+		proposalFieldBurnQuorum:      dataStr.BurnQuorum,
+		proposalFieldWhitelistQuorum: dataStr.WhitelistQuorum,
+		proposalFieldBlacklistQuorum: dataStr.BlacklistQuorum,
+		proposalFieldConfigQuorum:    dataStr.ConfigQuorum,
+	} {
+		if v != nil {
+			values[field] = quorumValue(v)
+		}
+	}
+	return values
+}
+
+// proposalFieldErrors checks the given proposal data against proposalFieldRules and
+// proposalFieldSpecs, i.e. the rules the proposal schema is generated from. Returns all problems
+// found.
+func proposalFieldErrors(action types.Action, dataStr *types.ProposalDataStr) []error {
+	rules, ok := proposalFieldRules[action]
+	if !ok {
+		return []error{fmt.Errorf("action '%s' cannot be proposed", action)}
+	}
+
+	var errs []error
+	values := proposalFieldValues(dataStr)
+	allowed := make(map[string]bool)
+	for _, field := range rules.required {
+		if _, set := values[field]; !set {
+			errs = append(errs, fmt.Errorf("field '%s' is required for action '%s'", field, action))
+		}
+		allowed[field] = true
+	}
+	for _, field := range rules.optional {
+		allowed[field] = true
+	}
+	for _, field := range proposalFieldNames {
+		value, set := values[field]
+		switch {
+		case !set:
+		case !allowed[field]:
+			errs = append(errs, fmt.Errorf("field '%s' is not allowed for action '%s'", field, action))
+		default:
+			if err := proposalFieldSpecs[field].check(field, value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(values) < rules.minFields {
+		errs = append(errs, fmt.Errorf("at least %d of the fields [%s] must be set for action '%s'", rules.minFields, strings.Join(rules.optional, ", "), action))
+	}
+
+	return errs
+}
+
+// checkProposalFields returns the first problem found by proposalFieldErrors.
+func checkProposalFields(action types.Action, dataStr *types.ProposalDataStr) error {
+	if errs := proposalFieldErrors(action, dataStr); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// parseProposal parses a proposal JSON document. Unknown fields are rejected so that typos in
+// field names are not silently ignored.
+func parseProposal(raw []byte) (types.Action, *types.ProposalDataStr, error) {
+	var doc struct {
+		Action string          `json:"action"`
+		Data   json.RawMessage `json:"data"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return types.NoAction, nil, fmt.Errorf("malformed proposal: %w", err)
+	}
+
+	action, err := types.ActionFromString(doc.Action)
+	if err != nil {
+		return types.NoAction, nil, fmt.Errorf("field 'action': %w", err)
+	}
+
+	var dataStr types.ProposalDataStr
+	if len(doc.Data) > 0 {
+		dec = json.NewDecoder(bytes.NewReader(doc.Data))
+		dec.DisallowUnknownFields()
+		if err = dec.Decode(&dataStr); err != nil {
+			return types.NoAction, nil, fmt.Errorf("malformed proposal data for action '%s': %w", action, err)
+		}
+	}
+
+	return action, &dataStr, nil
+}
+
+// newProposalData validates the proposal data for the given action and converts it into the
+// form expected by the runtime.
+func newProposalData(npa *common.NPASelection, action types.Action, dataStr *types.ProposalDataStr) (*types.ProposalData, error) {
+	if err := checkProposalFields(action, dataStr); err != nil {
+		return nil, err
+	}
+
+	var data types.ProposalData
+	if dataStr.Address != nil {
+		addr, err := common.ResolveLocalAccountOrAddress(npa.Network, *dataStr.Address)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", proposalFieldAddress, err)
+		}
+		data.Address = addr
+	}
+
+	if dataStr.Amount != nil {
+		amount, err := helpers.ParseParaTimeDenomination(npa.ParaTime, *dataStr.Amount, types.NativeDenomination)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", proposalFieldAmount, err)
+		}
+		data.Amount = amount
+	}
+
+	switch action {
+	case types.Mint, types.Burn:
+		meta, err := types.StringToMeta(dataStr.Meta)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", proposalFieldMeta, err)
+		}
+		data.Meta = meta
+	case types.SetRoles:
+		role, err := types.RoleFromString(*dataStr.Role)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", proposalFieldRole, err)
+		}
+		data.Role = &role
+	case types.Config:
+		data.MintQuorum = dataStr.MintQuorum
+		data.BurnQuorum = dataStr.BurnQuorum
+		data.WhitelistQuorum = dataStr.WhitelistQuorum
+		data.BlacklistQuorum = dataStr.BlacklistQuorum
+		data.ConfigQuorum = dataStr.ConfigQuorum
+	}
+
+	return &data, nil
+}
+
+// submitProposal signs and broadcasts a proposal with the given content.
+func submitProposal(npa *common.NPASelection, content *accounts.ProposalContent) {
+	cfg := cliConfig.Global()
+	txCfg := common.GetTransactionConfig()
+
+	if npa.Account == nil {
+		cobra.CheckErr("no accounts configured in your wallet")
+	}
+
+	// When not in offline mode, connect to the given network endpoint.
+	ctx := context.Background()
+	var conn connection.Connection
+	if !txCfg.Offline {
+		var err error
+		conn, err = connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		// Check the signer's role before unlocking the account.
+		common.CheckForceErr(preflightPropose(ctx, conn, npa, content.Action))
+	}
+
+	acc := common.LoadAccount(cfg, npa.AccountName)
+
+	// Prepare transaction.
+	tx := accounts.NewProposeTx(nil, content)
+
+	sigTx, meta, err := common.SignParaTimeTransaction(ctx, npa, acc, conn, tx)
+	cobra.CheckErr(err)
+
+	common.BroadcastTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
+}
+
+// quorumValue returns the value of the given optional quorum field, or zero if not set.
+func quorumValue[T ~uint8 | ~uint16 | ~uint32 | ~uint64](q *T) uint64 {
+	if q == nil {
+		return 0
+	}
+	return uint64(*q)
+}
+
+// setQuorum sets the given optional quorum field.
+func setQuorum[T ~uint8 | ~uint16 | ~uint32 | ~uint64](dst **T, value uint8) {
+	q := T(value)
+	*dst = &q
+}
+
+var (
+	proposeAddress         string
+	proposeAmount          string
+	proposeMeta            string
+	proposeMetaFile        string
+	proposeRole            string
+	proposeMintQuorum      uint8
+	proposeBurnQuorum      uint8
+	proposeWhitelistQuorum uint8
+	proposeBlacklistQuorum uint8
+	proposeConfigQuorum    uint8
+
+	managestProposeMintCmd = &cobra.Command{
+		Use:   "mint --to <address> --amount <amount> [--meta <meta>]",
+		Short: "Propose minting stable tokens to an account",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runProposeAction(cmd, types.Mint)
+		},
+	}
+
+	managestProposeBurnCmd = &cobra.Command{
+		Use:   "burn --from <address> --amount <amount> [--meta <meta>]",
+		Short: "Propose burning stable tokens of an account",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runProposeAction(cmd, types.Burn)
+		},
+	}
+
+	managestProposeSetRolesCmd = &cobra.Command{
+		Use:   "set-roles --address <address> --role <role>",
+		Short: "Propose assigning a role to an account",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runProposeAction(cmd, types.SetRoles)
+		},
+	}
+
+	managestProposeWhitelistCmd = &cobra.Command{
+		Use:   "whitelist --address <address>",
+		Short: "Propose adding an account to the whitelist",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runProposeAction(cmd, types.Whitelist)
+		},
+	}
+
+	managestProposeBlacklistCmd = &cobra.Command{
+		Use:   "blacklist --address <address>",
+		Short: "Propose adding an account to the blacklist",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runProposeAction(cmd, types.Blacklist)
+		},
+	}
+
+	managestProposeConfigCmd = &cobra.Command{
+		Use:   "config [--mint-quorum N] [--burn-quorum N] [--whitelist-quorum N] [--blacklist-quorum N] [--config-quorum N]",
+		Short: "Propose changing the quorums of proposal actions",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runProposeAction(cmd, types.Config)
+		},
+	}
+)
+
+// runProposeAction builds a proposal for the given action from the command flags and submits it.
+func runProposeAction(cmd *cobra.Command, action types.Action) {
+	cfg := cliConfig.Global()
+	npa := common.GetNPASelection(cfg)
+
+	if npa.ParaTime == nil {
+		cobra.CheckErr("no runtime configured")
+	}
+
+	var dataStr types.ProposalDataStr
+	for _, name := range []string{"to", "from", "address"} {
+		if cmd.Flags().Changed(name) {
+			dataStr.Address = &proposeAddress
+		}
+	}
+	if cmd.Flags().Changed("amount") {
+		dataStr.Amount = &proposeAmount
+	}
+	if cmd.Flags().Changed("meta") {
+		dataStr.Meta = &proposeMeta
+	}
+	setMetaFromFile(cmd, &dataStr)
+	if cmd.Flags().Changed("role") {
+		dataStr.Role = &proposeRole
+	}
+	setQuorumsFromFlags(cmd, &dataStr)
+
+	data, err := newProposalData(npa, action, &dataStr)
+	cobra.CheckErr(err)
+
+	content := &accounts.ProposalContent{
+		Action: action,
+		Data:   *data,
+	}
+	if proposeExportReview != "" {
+		exportReviewBundle(npa, &dataStr, content)
+		return
+	}
+	submitProposal(npa, content)
+}
+
+// newProposalQuorumFlags returns the flags for setting the quorums of a Config proposal.
+func newProposalQuorumFlags() *flag.FlagSet {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.Uint8Var(&proposeMintQuorum, "mint-quorum", 0, "new Mint quorum in percent")
+	f.Uint8Var(&proposeBurnQuorum, "burn-quorum", 0, "new Burn quorum in percent")
+	f.Uint8Var(&proposeWhitelistQuorum, "whitelist-quorum", 0, "new Whitelist quorum in percent")
+	f.Uint8Var(&proposeBlacklistQuorum, "blacklist-quorum", 0, "new Blacklist quorum in percent")
+	f.Uint8Var(&proposeConfigQuorum, "config-quorum", 0, "new Config quorum in percent")
+	return f
+}
+
+// setQuorumsFromFlags sets the quorum fields given via the quorum flags.
+func setQuorumsFromFlags(cmd *cobra.Command, dataStr *types.ProposalDataStr) {
+	if cmd.Flags().Changed("mint-quorum") {
+		setQuorum(&dataStr.MintQuorum, proposeMintQuorum)
+	}
+	if cmd.Flags().Changed("burn-quorum") {
+		setQuorum(&dataStr.BurnQuorum, proposeBurnQuorum)
+	}
+	if cmd.Flags().Changed("whitelist-quorum") {
+		setQuorum(&dataStr.WhitelistQuorum, proposeWhitelistQuorum)
+	}
+	if cmd.Flags().Changed("blacklist-quorum") {
+		setQuorum(&dataStr.BlacklistQuorum, proposeBlacklistQuorum)
+	}
+	if cmd.Flags().Changed("config-quorum") {
+		setQuorum(&dataStr.ConfigQuorum, proposeConfigQuorum)
+	}
+}
+
+func init() {
+	toFlag := flag.NewFlagSet("", flag.ContinueOnError)
+	toFlag.StringVar(&proposeAddress, "to", "", "account or address to mint to")
+	fromFlag := flag.NewFlagSet("", flag.ContinueOnError)
+	fromFlag.StringVar(&proposeAddress, "from", "", "account or address to burn from")
+	addressFlag := flag.NewFlagSet("", flag.ContinueOnError)
+	addressFlag.StringVar(&proposeAddress, "address", "", "account or address the proposal applies to")
+
+	amountFlags := flag.NewFlagSet("", flag.ContinueOnError)
+	amountFlags.StringVar(&proposeAmount, "amount", "", "amount of stable tokens")
+	amountFlags.StringVar(&proposeMeta, "meta", "", "proposal meta information (e.g. reference of the supporting document)")
+	amountFlags.AddFlagSet(newMetaFileFlag())
+
+	roleFlag := flag.NewFlagSet("", flag.ContinueOnError)
+	roleFlag.StringVar(&proposeRole, "role", "", "role to assign (e.g. Admin, MintProposer, MintVoter)")
+
+	managestProposeMintCmd.Flags().AddFlagSet(toFlag)
+	managestProposeMintCmd.Flags().AddFlagSet(amountFlags)
+	managestProposeBurnCmd.Flags().AddFlagSet(fromFlag)
+	managestProposeBurnCmd.Flags().AddFlagSet(amountFlags)
+	managestProposeSetRolesCmd.Flags().AddFlagSet(addressFlag)
+	managestProposeSetRolesCmd.Flags().AddFlagSet(roleFlag)
+	managestProposeWhitelistCmd.Flags().AddFlagSet(addressFlag)
+	managestProposeBlacklistCmd.Flags().AddFlagSet(addressFlag)
+	managestProposeConfigCmd.Flags().AddFlagSet(newProposalQuorumFlags())
+
+	for _, cmd := range []*cobra.Command{
+		managestProposeMintCmd,
+		managestProposeBurnCmd,
+		managestProposeSetRolesCmd,
+		managestProposeWhitelistCmd,
+		managestProposeBlacklistCmd,
+		managestProposeConfigCmd,
+	} {
+		cmd.Flags().AddFlagSet(common.SelectorFlags)
+		cmd.Flags().AddFlagSet(common.TransactionFlags)
+		cmd.Flags().AddFlagSet(common.ForceFlag)
+		cmd.Flags().AddFlagSet(newExportReviewFlag())
+
+		managestProposalCmd.AddCommand(cmd)
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestProposalFieldErrorsAmount(t *testing.T) {
+	require := require.New(t)
+
+	addr := "alice"
+	for _, tc := range []struct {
+		amount string
+		valid  bool
+	}{
+		{"100", true},
+		{"0.5", true},
+		{"10.0", true},
+		{"0.0", false},
+		{"0", false},
+		{"00.00", false},
+		{"1e5", false},
+		{"-1", false},
+		{".5", false},
+		{"1,000", false},
+	} {
+		amount := tc.amount
+		errs := proposalFieldErrors(types.Mint, &types.ProposalDataStr{Address: &addr, Amount: &amount})
+		if tc.valid {
+			require.Empty(errs, "amount %s", tc.amount)
+		} else {
+			require.Len(errs, 1, "amount %s", tc.amount)
+			require.ErrorContains(errs[0], "is not a positive decimal amount", "amount %s", tc.amount)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+var (
+	proposeExportReview string
+	proposeFromReview   string
+	proposeExpectHash   string
+)
+
+// reviewBundle is an offline review bundle of a proposal. The data is stored in canonical form
+// (addresses in Bech32, amounts in display units) so that the proposal can be rebuilt from it.
+type reviewBundle struct {
+	Network      string                 `json:"network"`
+	ChainContext string                 `json:"chain_context"`
+	ParaTime     string                 `json:"paratime"`
+	ParaTimeID   string                 `json:"paratime_id"`
+	Action       string                 `json:"action"`
+	Data         *types.ProposalDataStr `json:"data"`
+	Summary      map[string]string      `json:"summary"`
+	ContentHash  string                 `json:"content_hash"`
+}
+
+// proposalContentHash returns the hash of the given proposal content bound to the selected
+// network and ParaTime.
+func proposalContentHash(npa *common.NPASelection, content *accounts.ProposalContent) string {
+	h := sha512.Sum512_256(cbor.Marshal(struct {
+		ChainContext string                    `json:"chain_context"`
+		ParaTimeID   string                    `json:"paratime_id"`
+		Content      *accounts.ProposalContent `json:"content"`
+	}{
+		ChainContext: npa.Network.ChainContext,
+		ParaTimeID:   npa.ParaTime.ID,
+		Content:      content,
+	}))
+	return hex.EncodeToString(h[:])
+}
+
+// newReviewBundle builds the review bundle of the given proposal.
+func newReviewBundle(npa *common.NPASelection, dataStr *types.ProposalDataStr, content *accounts.ProposalContent) *reviewBundle {
+	cfg := cliConfig.Global()
+	data := content.Data
+
+	canonical := *dataStr
+	summary := map[string]string{
+		"action": content.Action.String(),
+	}
+	if data.Address != nil {
+		addr := data.Address.String()
+		canonical.Address = &addr
+		summary[proposalFieldAddress] = addr
+		if name := common.FindAccountName(cfg, addr); name != "" {
+			summary[proposalFieldAddress] = fmt.Sprintf("%s (%s)", addr, name)
+		}
+	}
+	if data.Amount != nil {
+		di := npa.ParaTime.GetDenominationInfo(data.Amount.Denomination)
+		amount := prettyprint.QuantityFrac(data.Amount.Amount, di.Decimals)
+		canonical.Amount = &amount
+		summary[proposalFieldAmount] = helpers.FormatParaTimeDenomination(npa.ParaTime, *data.Amount)
+	}
+	if data.Role != nil {
+		role := data.Role.String()
+		canonical.Role = &role
+		summary[proposalFieldRole] = role
+	}
+	if dataStr.Meta != nil {
+		summary[proposalFieldMeta] = *dataStr.Meta
+	}
+	for action, quorum := range proposedQuorums(dataStr) {
+		summary[strings.ToLower(action.String())+"_quorum"] = fmt.Sprintf("%d%%", quorum)
+	}
+
+	return &reviewBundle{
+		Network:      npa.NetworkName,
+		ChainContext: npa.Network.ChainContext,
+		ParaTime:     npa.ParaTimeName,
+		ParaTimeID:   npa.ParaTime.ID,
+		Action:       content.Action.String(),
+		Data:         &canonical,
+		Summary:      summary,
+		ContentHash:  proposalContentHash(npa, content),
+	}
+}
+
+// exportReviewBundle writes the review bundle of the given proposal to the file given via
+// --export-review.
+func exportReviewBundle(npa *common.NPASelection, dataStr *types.ProposalDataStr, content *accounts.ProposalContent) {
+	bundle := newReviewBundle(npa, dataStr, content)
+	raw, err := json.MarshalIndent(bundle, "", "  ")
+	cobra.CheckErr(err)
+	cobra.CheckErr(ioutil.WriteFile(proposeExportReview, append(raw, '\n'), 0o600))
+
+	fmt.Printf("Review bundle written to %s.\n", proposeExportReview)
+	fmt.Printf("Content hash: %s\n", bundle.ContentHash)
+}
+
+// loadReviewBundle loads the proposal from the review bundle given via --from-review and checks
+// that it matches the selected network and ParaTime and the hash given via --expect-hash.
+func loadReviewBundle(npa *common.NPASelection) (*types.ProposalDataStr, *accounts.ProposalContent) {
+	if proposeExpectHash == "" {
+		cobra.CheckErr("--from-review requires --expect-hash with the hash approved by the reviewer")
+	}
+
+	raw, err := ioutil.ReadFile(proposeFromReview)
+	cobra.CheckErr(err)
+	var bundle reviewBundle
+	if err = json.Unmarshal(raw, &bundle); err != nil {
+		cobra.CheckErr(fmt.Errorf("malformed review bundle: %w", err))
+	}
+
+	if bundle.ChainContext != npa.Network.ChainContext || bundle.ParaTimeID != npa.ParaTime.ID {
+		cobra.CheckErr(fmt.Errorf("review bundle targets %s/%s, but %s/%s is selected", bundle.Network, bundle.ParaTime, npa.NetworkName, npa.ParaTimeName))
+	}
+
+	action, err := types.ActionFromString(bundle.Action)
+	cobra.CheckErr(err)
+	if bundle.Data == nil {
+		bundle.Data = &types.ProposalDataStr{}
+	}
+	data, err := newProposalData(npa, action, bundle.Data)
+	cobra.CheckErr(err)
+	content := &accounts.ProposalContent{
+		Action: action,
+		Data:   *data,
+	}
+
+	hash := proposalContentHash(npa, content)
+	if !strings.EqualFold(hash, strings.TrimSpace(proposeExpectHash)) {
+		cobra.CheckErr(fmt.Errorf("proposal content hash %s does not match the approved hash %s", hash, proposeExpectHash))
+	}
+	if !strings.EqualFold(hash, bundle.ContentHash) {
+		cobra.CheckErr(fmt.Errorf("review bundle has been modified: content hash %s does not match the recorded hash %s", hash, bundle.ContentHash))
+	}
+	return bundle.Data, content
+}
+
+// newExportReviewFlag returns the flag for exporting a review bundle instead of signing.
+func newExportReviewFlag() *flag.FlagSet {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.StringVar(&proposeExportReview, "export-review", "", "write an offline review bundle of the proposal to the given file instead of signing it")
+	return f
+}
+
+// newFromReviewFlags returns the flags for signing a proposal from a reviewed bundle.
+func newFromReviewFlags() *flag.FlagSet {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.StringVar(&proposeFromReview, "from-review", "", "sign the proposal from the given review bundle")
+	f.StringVar(&proposeExpectHash, "expect-hash", "", "content hash approved by the reviewer")
+	return f
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+	"github.com/oasisprotocol/cli/table"
+)
+
+// Kinds of role membership changes.
+const (
+	roleChangeMember  = "member"
+	roleChangeAdded   = "added"
+	roleChangeRemoved = "removed"
+)
+
+var (
+	rolesHistoryFromRound uint64
+	rolesHistoryStep      uint64
+
+	managestRolesHistoryCmd = &cobra.Command{
+		Use:   "roles-history",
+		Short: "Show the history of role team membership changes",
+		Long: "Show a chronological audit log of addresses gaining or losing roles from --from-round up to " +
+			"the round selected by --round or --height (default: latest round) together with the SetRoles " +
+			"proposal that caused the change.\n\n" +
+			"Role teams are sampled every --step rounds and the exact round of each change is found by " +
+			"bisection. Changes that are reverted within a single step are not visible, use --step 1 to " +
+			"inspect every round. The log starts with the team members at the first round.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg := cliConfig.Global()
+			npa := common.GetNPASelection(cfg)
+			format := common.GetOutputFormat()
+
+			if npa.ParaTime == nil {
+				cobra.CheckErr("no runtime configured")
+			}
+			if rolesHistoryStep == 0 {
+				cobra.CheckErr("step must be at least 1")
+			}
+
+			// Establish connection with the target network.
+			ctx := context.Background()
+			conn, err := connection.Connect(ctx, npa.Network)
+			cobra.CheckErr(err)
+
+			fromRound := rolesHistoryFromRound
+			if !cmd.Flags().Changed("from-round") {
+				blk, err := conn.Runtime(npa.ParaTime).GetLastRetainedBlock(ctx)
+				cobra.CheckErr(err)
+				fromRound = blk.Header.Round
+			}
+			toRound, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+			cobra.CheckErr(err)
+			if fromRound > toRound {
+				cobra.CheckErr(fmt.Errorf("from round %d is after to round %d", fromRound, toRound))
+			}
+
+			h, err := newRolesHistory(ctx, conn, npa, toRound)
+			cobra.CheckErr(err)
+			changes, err := h.collect(ctx, fromRound, toRound, rolesHistoryStep)
+			cobra.CheckErr(err)
+
+			for _, change := range changes {
+				change.Name = common.FindAccountName(cfg, change.Address.String())
+			}
+
+			switch format {
+			case common.FormatJSON, common.FormatYAML:
+				common.PrintStructured(format, changes)
+			default:
+				output := make([][]string, 0, len(changes))
+				for _, change := range changes {
+					addr := change.Address.String()
+					if change.Name != "" {
+						addr = fmt.Sprintf("%s (%s)", addr, change.Name)
+					}
+					var proposal string
+					if change.ProposalID != nil {
+						proposal = fmt.Sprintf("%d", *change.ProposalID)
+					}
+					output = append(output, []string{
+						fmt.Sprintf("%d", change.Round),
+						change.Change,
+						change.Role,
+						addr,
+						proposal,
+					})
+				}
+
+				fmt.Printf("Role changes between rounds %d and %d:\n", fromRound, toRound)
+				table := table.New()
+				table.SetHeader([]string{"Round", "Change", "Role", "Address", "Proposal"})
+				table.AppendBulk(output)
+				table.Render()
+			}
+		},
+	}
+)
+
+// roleChange is a single entry of the role membership audit log.
+type roleChange struct {
+	Round      uint64        `json:"round"`
+	Change     string        `json:"change"`
+	Role       string        `json:"role"`
+	Address    types.Address `json:"address"`
+	Name       string        `json:"name,omitempty"`
+	ProposalID *uint32       `json:"proposal_id,omitempty"`
+}
+
+// rolesHistory reconstructs role membership changes.
+type rolesHistory struct {
+	conn connection.Connection
+	npa  *common.NPASelection
+
+	// setRoles are the SetRoles proposals keyed by the address they apply to.
+	setRoles map[types.Address][]uint32
+}
+
+func newRolesHistory(ctx context.Context, conn connection.Connection, npa *common.NPASelection, round uint64) (*rolesHistory, error) {
+	h := &rolesHistory{
+		conn:     conn,
+		npa:      npa,
+		setRoles: make(map[types.Address][]uint32),
+	}
+
+	latestID, err := conn.Runtime(npa.ParaTime).Accounts.ProposalIDInfo(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	for id := uint32(0); id <= latestID; id++ {
+		proposal, err := fetchProposal(ctx, conn, npa.ParaTime, round, id)
+		if err != nil {
+			return nil, err
+		}
+		if proposal == nil || proposal.Raw.Action != types.SetRoles || proposal.Raw.Data.Address == nil {
+			continue
+		}
+		addr := *proposal.Raw.Data.Address
+		h.setRoles[addr] = append(h.setRoles[addr], proposal.ID)
+	}
+	return h, nil
+}
+
+// roleAssignments returns the role of each address that is a member of a role team.
+func roleAssignments(teams map[types.Role][]types.Address) map[types.Address]types.Role {
+	roles := make(map[types.Address]types.Role)
+	for role, addrs := range teams {
+		for _, addr := range addrs {
+			roles[addr] = role
+		}
+	}
+	return roles
+}
+
+// collect returns all role changes in the given (inclusive) round range.
+func (h *rolesHistory) collect(ctx context.Context, fromRound, toRound, step uint64) ([]*roleChange, error) {
+	pt := h.npa.ParaTime
+
+	teams, err := queryRoleTeams(ctx, h.conn, pt, fromRound)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*roleChange
+	for role := types.Admin; role < types.User; role++ {
+		for _, addr := range teams[role] {
+			changes = append(changes, &roleChange{Round: fromRound, Change: roleChangeMember, Role: role.String(), Address: addr})
+		}
+	}
+
+	prevRound, prevRoles := fromRound, roleAssignments(teams)
+	for prevRound < toRound {
+		round := prevRound + step
+		if round > toRound || round < prevRound {
+			round = toRound
+		}
+
+		teams, err = queryRoleTeams(ctx, h.conn, pt, round)
+		if err != nil {
+			return nil, err
+		}
+		roles := roleAssignments(teams)
+
+		var stepChanges []*roleChange
+		for _, addr := range changedAddresses(prevRoles, roles) {
+			oldRole, ok := prevRoles[addr]
+			if !ok {
+				oldRole = types.User
+			}
+			newRole, ok := roles[addr]
+			if !ok {
+				newRole = types.User
+			}
+
+			changedRound, err := h.findRoleChange(ctx, addr, oldRole, prevRound+1, round)
+			if err != nil {
+				return nil, err
+			}
+			proposalID, err := h.findProposal(ctx, addr, changedRound)
+			if err != nil {
+				return nil, err
+			}
+
+			if oldRole != types.User {
+				stepChanges = append(stepChanges, &roleChange{Round: changedRound, Change: roleChangeRemoved, Role: oldRole.String(), Address: addr, ProposalID: proposalID})
+			}
+			if newRole != types.User {
+				stepChanges = append(stepChanges, &roleChange{Round: changedRound, Change: roleChangeAdded, Role: newRole.String(), Address: addr, ProposalID: proposalID})
+			}
+		}
+		sort.SliceStable(stepChanges, func(i, j int) bool { return stepChanges[i].Round < stepChanges[j].Round })
+		changes = append(changes, stepChanges...)
+
+		prevRound, prevRoles = round, roles
+	}
+	return changes, nil
+}
+
+// changedAddresses returns the addresses whose role differs between the two assignments.
+func changedAddresses(old, new map[types.Address]types.Role) []types.Address {
+	var addrs []types.Address
+	for addr, role := range old {
+		if newRole, ok := new[addr]; !ok || newRole != role {
+			addrs = append(addrs, addr)
+		}
+	}
+	for addr := range new {
+		if _, ok := old[addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].String() < addrs[j].String() })
+	return addrs
+}
+
+// findRoleChange returns the first round in the given (inclusive) range at which the role of the
+// given address differs from the old role.
+func (h *rolesHistory) findRoleChange(ctx context.Context, addr types.Address, oldRole types.Role, lo, hi uint64) (uint64, error) {
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		role, err := h.conn.Runtime(h.npa.ParaTime).Accounts.Role(ctx, mid, addr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to query role of %s at round %d: %w", addr, mid, err)
+		}
+		if role != oldRole {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
+
+// findProposal returns the ID of the SetRoles proposal for the given address that was executed in
+// the given round, if any.
+func (h *rolesHistory) findProposal(ctx context.Context, addr types.Address, round uint64) (*uint32, error) {
+	proposal, err := findClosedProposal(ctx, h.conn, h.npa.ParaTime, h.setRoles[addr], round)
+	if err != nil || proposal == nil {
+		return nil, err
+	}
+	return &proposal.ID, nil
+}
+
+func init() {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.Uint64Var(&rolesHistoryFromRound, "from-round", 0, "first round to inspect (default: last retained round)")
+	f.Uint64Var(&rolesHistoryStep, "step", 100, "number of rounds between role team samples")
+
+	managestRolesHistoryCmd.Flags().AddFlagSet(common.SelectorNPFlags)
+	managestRolesHistoryCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestRolesHistoryCmd.Flags().AddFlagSet(common.FormatFlag)
+	managestRolesHistoryCmd.Flags().AddFlagSet(f)
+
+	managestCmd.AddCommand(managestRolesHistoryCmd)
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+// proposedQuorums returns the quorums set by a Config proposal keyed by action.
+func proposedQuorums(dataStr *types.ProposalDataStr) map[types.Action]uint64 {
+	quorums := make(map[types.Action]uint64)
+	for action, quorum := range map[types.Action]*uint8{
+		types.Mint:      dataStr.MintQuorum,
+		types.Burn:      dataStr.BurnQuorum,
+		types.Whitelist: dataStr.WhitelistQuorum,
+		types.Blacklist: dataStr.BlacklistQuorum,
+		types.Config:    dataStr.ConfigQuorum,
+	} {
+		if quorum != nil {
+			quorums[action] = uint64(*quorum)
+		}
+	}
+	return quorums
+}
+
+// formatQuorum formats a quorum together with the number of required votes.
+func formatQuorum(tally *proposalTally) string {
+	if tally.NoVotesRequired() {
+		return "0% (no votes required)"
+	}
+	return fmt.Sprintf("%d%% (%d of %d)", tally.Quorum, tally.Required(), tally.TeamSize)
+}
+
+var managestSimulateConfigCmd = &cobra.Command{
+	Use:   "simulate-config [<proposal.json>] [--mint-quorum N] [--burn-quorum N] [--whitelist-quorum N] [--blacklist-quorum N] [--config-quorum N]",
+	Short: "Simulate the effect of a Config proposal on quorums and open proposals",
+	Long: "Apply the quorums of a Config proposal file and/or the quorum flags to the current quorums and " +
+		"role team sizes. Reports the number of votes each action would require, whether any action " +
+		"could no longer pass or would pass without any votes (0% quorum) and which open proposals would flip between passing and failing. Flags " +
+		"take precedence over the proposal file.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+
+		if npa.ParaTime == nil {
+			cobra.CheckErr("no runtime configured")
+		}
+
+		var dataStr types.ProposalDataStr
+		if len(args) > 0 {
+			rawProposal, err := ioutil.ReadFile(args[0])
+			cobra.CheckErr(err)
+
+			action, fileDataStr, err := parseProposal(rawProposal)
+			cobra.CheckErr(err)
+			if action != types.Config {
+				cobra.CheckErr(fmt.Errorf("expected a %s proposal, got %s", types.Config, action))
+			}
+			dataStr = *fileDataStr
+		}
+		setQuorumsFromFlags(cmd, &dataStr)
+		cobra.CheckErr(checkProposalFields(types.Config, &dataStr))
+		proposed := proposedQuorums(&dataStr)
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+		cobra.CheckErr(err)
+
+		current, err := queryQuorums(ctx, conn, npa.ParaTime, round)
+		cobra.CheckErr(err)
+		teams, err := queryRoleTeams(ctx, conn, npa.ParaTime, round)
+		cobra.CheckErr(err)
+
+		// Build the current and the simulated tallies of each action.
+		currentTallies := make(map[types.Action]*proposalTally)
+		newTallies := make(map[types.Action]*proposalTally)
+		var output [][]string
+		var impossible, unvoted int
+		for action := types.SetRoles; action <= types.Config; action++ {
+			voterRole, err := voterRoleForAction(action)
+			cobra.CheckErr(err)
+			teamSize := uint64(len(teams[voterRole]))
+
+			newQuorum, changed := proposed[action]
+			if !changed {
+				newQuorum = current[action]
+			}
+			currentTallies[action] = &proposalTally{Quorum: current[action], TeamSize: teamSize}
+			newTallies[action] = &proposalTally{Quorum: newQuorum, TeamSize: teamSize}
+
+			status := "unchanged"
+			if changed && newQuorum != current[action] {
+				status = "changed"
+			}
+			switch {
+			case newTallies[action].NoVotesRequired():
+				status = "NO VOTES REQUIRED"
+				unvoted++
+			case !newTallies[action].Reachable():
+				status = "IMPOSSIBLE"
+				impossible++
+			}
+			output = append(output, []string{
+				action.String(),
+				fmt.Sprintf("%s (%d)", voterRole, teamSize),
+				formatQuorum(currentTallies[action]),
+				formatQuorum(newTallies[action]),
+				status,
+			})
+		}
+
+		fmt.Printf("Quorums at round %d:\n", round)
+		renderTable([]string{"Action", "Voter team", "Current", "Simulated", "Status"}, output)
+
+		// Find open proposals whose outcome would change.
+		latestID, err := conn.Runtime(npa.ParaTime).Accounts.ProposalIDInfo(ctx, round)
+		cobra.CheckErr(err)
+
+		output = nil
+		for id := int64(latestID); id >= int64(proposalRangeStart(cmd, latestID)); id-- {
+			proposal, err := fetchProposal(ctx, conn, npa.ParaTime, round, uint32(id))
+			cobra.CheckErr(err)
+			if proposal == nil || !proposal.isOpen() {
+				continue
+			}
+
+			action := proposal.Raw.Action
+			before := newProposalTally(proposal.Results, currentTallies[action].Quorum, currentTallies[action].TeamSize)
+			after := newProposalTally(proposal.Results, newTallies[action].Quorum, newTallies[action].TeamSize)
+			passesBefore, passesAfter := before.Missing() == 0, after.Missing() == 0
+			canPassBefore, canPassAfter := before.CanPass(), after.CanPass()
+
+			var effect string
+			switch {
+			case !passesBefore && passesAfter:
+				effect = "would pass"
+			case passesBefore && !passesAfter:
+				effect = "would no longer pass"
+			case canPassBefore && !canPassAfter:
+				effect = "could no longer pass"
+			case !canPassBefore && canPassAfter:
+				effect = "could pass again"
+			default:
+				continue
+			}
+			output = append(output, []string{
+				fmt.Sprintf("%d", proposal.ID),
+				proposal.ActionName,
+				fmt.Sprintf("%d", after.Yes),
+				fmt.Sprintf("%d", before.Required()),
+				fmt.Sprintf("%d", after.Required()),
+				effect,
+			})
+		}
+
+		fmt.Println()
+		if len(output) == 0 {
+			fmt.Println("No open proposals would change their outcome.")
+		} else {
+			fmt.Println("Open proposals affected by the change:")
+			renderTable([]string{"ID", "Action", "Yes", "Required now", "Required after", "Effect"}, output)
+		}
+
+		if impossible > 0 || unvoted > 0 {
+			fmt.Println()
+		}
+		if impossible > 0 {
+			fmt.Printf("WARNING: %d action(s) could no longer pass with the simulated quorums.\n", impossible)
+		}
+		if unvoted > 0 {
+			fmt.Printf("WARNING: %d action(s) would pass without any votes with the simulated quorums.\n", unvoted)
+		}
+	},
+}
+
+func init() {
+	managestSimulateConfigCmd.Flags().AddFlagSet(common.SelectorNPFlags)
+	managestSimulateConfigCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestSimulateConfigCmd.Flags().AddFlagSet(newProposalQuorumFlags())
+	managestSimulateConfigCmd.Flags().AddFlagSet(newProposalRangeFlags())
+
+	managestCmd.AddCommand(managestSimulateConfigCmd)
+}
@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+// Stablecoin address lists.
+const (
+	stablecoinListWhitelist = "whitelist"
+	stablecoinListBlacklist = "blacklist"
+)
+
+// stablecoinListsCacheFile is the name of the file caching the stablecoin lists of all networks.
+const stablecoinListsCacheFile = "stablecoin_lists.json"
+
+// stablecoinListing is the listing of an address on the whitelist or the blacklist.
+type stablecoinListing struct {
+	Address    types.Address `json:"address"`
+	Name       string        `json:"name,omitempty"`
+	List       string        `json:"list"`
+	ProposalID uint32        `json:"proposal_id"`
+	Round      uint64        `json:"round"`
+}
+
+// stablecoinLists are the whitelist and the blacklist. Addresses are never removed from either list,
+// so an address may be on both lists at the same time.
+type stablecoinLists struct {
+	Whitelist map[types.Address]*stablecoinListing `json:"whitelist"`
+	Blacklist map[types.Address]*stablecoinListing `json:"blacklist"`
+}
+
+func newStablecoinLists() *stablecoinLists {
+	return &stablecoinLists{
+		Whitelist: make(map[types.Address]*stablecoinListing),
+		Blacklist: make(map[types.Address]*stablecoinListing),
+	}
+}
+
+// add records the listing unless the address has already been added to the list earlier.
+func (l *stablecoinLists) add(listing *stablecoinListing) {
+	list := l.Whitelist
+	if listing.List == stablecoinListBlacklist {
+		list = l.Blacklist
+	}
+	if prev, ok := list[listing.Address]; ok && prev.Round <= listing.Round {
+		return
+	}
+	list[listing.Address] = listing
+}
+
+// lookup returns the listings of the given address.
+func (l *stablecoinLists) lookup(addr types.Address) []*stablecoinListing {
+	var listings []*stablecoinListing
+	for _, list := range []map[types.Address]*stablecoinListing{l.Whitelist, l.Blacklist} {
+		if listing, ok := list[addr]; ok {
+			listings = append(listings, listing)
+		}
+	}
+	return listings
+}
+
+// stablecoinListsCache is the cached state of the stablecoin lists of a ParaTime.
+type stablecoinListsCache struct {
+	// NextID is the lowest proposal ID that did not exist or was still open at the cached scan.
+	NextID uint32 `json:"next_id"`
+	// Lists are the lists built from all proposals below NextID.
+	Lists *stablecoinLists `json:"lists"`
+}
+
+func stablecoinListsCachePath() string {
+	return filepath.Join(cliConfig.Directory(), stablecoinListsCacheFile)
+}
+
+func stablecoinListsCacheKey(npa *common.NPASelection) string {
+	return fmt.Sprintf("%s/%s/%s", npa.NetworkName, npa.ParaTimeName, npa.ParaTime.ID)
+}
+
+// loadStablecoinListsCache returns the cached stablecoin lists of the selected ParaTime, or nil if
+// there are none.
+func loadStablecoinListsCache(npa *common.NPASelection) *stablecoinListsCache {
+	raw, err := ioutil.ReadFile(stablecoinListsCachePath())
+	if err != nil {
+		return nil
+	}
+	return parseStablecoinListsCache(raw, stablecoinListsCacheKey(npa))
+}
+
+// parseStablecoinListsCache returns the cache entry with the given key, or nil if there is none or
+// the entry is incomplete, e.g. due to a truncated or manually edited file.
+func parseStablecoinListsCache(raw []byte, key string) *stablecoinListsCache {
+	var all map[string]*stablecoinListsCache
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil
+	}
+	c := all[key]
+	if c == nil || c.Lists == nil || c.Lists.Whitelist == nil || c.Lists.Blacklist == nil {
+		return nil
+	}
+	for _, list := range []map[types.Address]*stablecoinListing{c.Lists.Whitelist, c.Lists.Blacklist} {
+		for _, listing := range list {
+			if listing == nil {
+				return nil
+			}
+		}
+	}
+	return c
+}
+
+// saveStablecoinListsCache stores the stablecoin lists of the selected ParaTime. Failures only
+// cause the lists to be rebuilt the next time, so they are reported as warnings.
+func saveStablecoinListsCache(npa *common.NPASelection, cache *stablecoinListsCache) {
+	all := make(map[string]*stablecoinListsCache)
+	if raw, err := ioutil.ReadFile(stablecoinListsCachePath()); err == nil {
+		_ = json.Unmarshal(raw, &all)
+	}
+	all[stablecoinListsCacheKey(npa)] = cache
+
+	err := func() error {
+		raw, err := json.Marshal(all)
+		if err != nil {
+			return err
+		}
+		if err = os.MkdirAll(filepath.Dir(stablecoinListsCachePath()), 0o700); err != nil {
+			return err
+		}
+		tmpPath := stablecoinListsCachePath() + ".tmp"
+		if err = ioutil.WriteFile(tmpPath, raw, 0o600); err != nil {
+			return err
+		}
+		return os.Rename(tmpPath, stablecoinListsCachePath())
+	}()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update stablecoin lists cache: %s\n", err)
+	}
+}
+
+// queryStablecoinLists reconstructs the whitelist and the blacklist from the executed Whitelist
+// and Blacklist proposals at the given round. When cached is set, the lists of the latest round
+// are read from the local cache and only proposals that were not yet closed at the last query are
+// looked at.
+func queryStablecoinLists(ctx context.Context, conn connection.Connection, npa *common.NPASelection, round uint64, cached bool) (*stablecoinLists, error) {
+	pt := npa.ParaTime
+	latestID, err := conn.Runtime(pt).Accounts.ProposalIDInfo(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &stablecoinListsCache{Lists: newStablecoinLists()}
+	if cached {
+		// Discard the cache if the proposals it covers no longer exist, e.g. after a network reset.
+		if c := loadStablecoinListsCache(npa); c != nil && c.NextID <= latestID+1 {
+			cache = c
+		}
+	}
+
+	nextID := latestID + 1
+	for id := cache.NextID; id <= latestID; id++ {
+		proposal, err := fetchProposal(ctx, conn, pt, round, id)
+		if err != nil {
+			return nil, err
+		}
+		if proposal == nil || proposal.isOpen() {
+			if id < nextID {
+				nextID = id
+			}
+			continue
+		}
+		if !proposal.isExecuted() || proposal.Raw.Data.Address == nil {
+			continue
+		}
+
+		var list string
+		switch proposal.Raw.Action {
+		case types.Whitelist:
+			list = stablecoinListWhitelist
+		case types.Blacklist:
+			list = stablecoinListBlacklist
+		default:
+			continue
+		}
+
+		executedRound, err := findProposalCloseRound(ctx, conn, pt, proposal.ID, round)
+		if err != nil {
+			return nil, err
+		}
+		cache.Lists.add(&stablecoinListing{
+			Address:    *proposal.Raw.Data.Address,
+			List:       list,
+			ProposalID: proposal.ID,
+			Round:      executedRound,
+		})
+	}
+
+	if cached {
+		cache.NextID = nextID
+		saveStablecoinListsCache(npa, cache)
+	}
+	return cache.Lists, nil
+}
+
+var managestListStatusCmd = &cobra.Command{
+	Use:   "list-status [address]",
+	Short: "Show the whitelist and blacklist status of stablecoin addresses",
+	Long: "Show whether the given account or address is whitelisted or blacklisted, or list all listed " +
+		"addresses when no address is given. The lists are reconstructed from executed Whitelist and " +
+		"Blacklist proposals. An address may be on both lists.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+		format := common.GetOutputFormat()
+
+		if npa.ParaTime == nil {
+			cobra.CheckErr("no runtime configured")
+		}
+
+		var addr *types.Address
+		if len(args) > 0 {
+			var err error
+			addr, err = common.ResolveLocalAccountOrAddress(npa.Network, args[0])
+			cobra.CheckErr(err)
+		}
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+		cobra.CheckErr(err)
+
+		// Only the lists of the latest round are cached.
+		lists, err := queryStablecoinLists(ctx, conn, npa, round, !cmd.Flags().Changed("round") && !cmd.Flags().Changed("height"))
+		cobra.CheckErr(err)
+
+		if addr != nil {
+			listings := lists.lookup(*addr)
+			for _, listing := range listings {
+				listing.Name = common.FindAccountName(cfg, addr.String())
+			}
+			if format != common.FormatTable {
+				if listings == nil {
+					listings = []*stablecoinListing{}
+				}
+				common.PrintStructured(format, listings)
+				return
+			}
+
+			if len(listings) == 0 {
+				fmt.Printf("%s is not listed.\n", addr)
+				return
+			}
+			for _, listing := range listings {
+				fmt.Printf("%s is on the %s since round %d (proposal %d).\n", addr, listing.List, listing.Round, listing.ProposalID)
+			}
+			return
+		}
+
+		var all []*stablecoinListing
+		for _, list := range []map[types.Address]*stablecoinListing{lists.Whitelist, lists.Blacklist} {
+			for _, listing := range list {
+				listing.Name = common.FindAccountName(cfg, listing.Address.String())
+				all = append(all, listing)
+			}
+		}
+		sort.Slice(all, func(i, j int) bool {
+			if all[i].List != all[j].List {
+				return all[i].List < all[j].List
+			}
+			return all[i].Round < all[j].Round
+		})
+
+		if format != common.FormatTable {
+			common.PrintStructured(format, all)
+			return
+		}
+
+		output := make([][]string, 0, len(all))
+		for _, listing := range all {
+			output = append(output, []string{
+				listing.List,
+				listing.Address.String(),
+				listing.Name,
+				fmt.Sprintf("%d", listing.ProposalID),
+				fmt.Sprintf("%d", listing.Round),
+			})
+		}
+		renderTable([]string{"List", "Address", "Name", "Proposal", "Round"}, output)
+	},
+}
+
+func init() {
+	managestListStatusCmd.Flags().AddFlagSet(common.SelectorNPFlags)
+	managestListStatusCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestListStatusCmd.Flags().AddFlagSet(common.FormatFlag)
+
+	managestCmd.AddCommand(managestListStatusCmd)
+}
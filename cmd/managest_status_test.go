@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestStablecoinLists(t *testing.T) {
+	require := require.New(t)
+
+	alice := types.NewAddressForModule("test", []byte("alice"))
+	bob := types.NewAddressForModule("test", []byte("bob"))
+
+	lists := newStablecoinLists()
+	lists.add(&stablecoinListing{Address: alice, List: stablecoinListBlacklist, ProposalID: 1, Round: 10})
+	lists.add(&stablecoinListing{Address: alice, List: stablecoinListWhitelist, ProposalID: 2, Round: 20})
+	lists.add(&stablecoinListing{Address: alice, List: stablecoinListWhitelist, ProposalID: 3, Round: 30})
+
+	require.Contains(lists.Blacklist, alice, "later whitelisting must not hide the blacklisting")
+	require.EqualValues(2, lists.Whitelist[alice].ProposalID, "earliest listing wins")
+	require.Len(lists.lookup(alice), 2)
+	require.Empty(lists.lookup(bob))
+}
+
+func TestParseStablecoinListsCache(t *testing.T) {
+	require := require.New(t)
+
+	alice := types.NewAddressForModule("test", []byte("alice"))
+
+	for _, tc := range []struct {
+		raw   string
+		valid bool
+	}{
+		{`{"k":{"next_id":3,"lists":{"whitelist":{},"blacklist":{}}}}`, true},
+		{`{"k":{"next_id":3,"lists":{"whitelist":{"` + alice.String() + `":{"round":10}},"blacklist":{}}}}`, true},
+		{`{"k":{"next_id":3,"lists":{"whitelist":null,"blacklist":{}}}}`, false},
+		{`{"k":{"next_id":3,"lists":{"whitelist":{}}}}`, false},
+		{`{"k":{"next_id":3,"lists":{"whitelist":{"` + alice.String() + `":null},"blacklist":{}}}}`, false},
+		{`{"k":{"next_id":3}}`, false},
+		{`{"other":{"next_id":3,"lists":{"whitelist":{},"blacklist":{}}}}`, false},
+		{`not json`, false},
+	} {
+		c := parseStablecoinListsCache([]byte(tc.raw), "k")
+		if !tc.valid {
+			require.Nil(c, tc.raw)
+			continue
+		}
+		require.NotNil(c, tc.raw)
+		c.Lists.add(&stablecoinListing{Address: alice, List: stablecoinListBlacklist, Round: 5})
+	}
+}
@@ -0,0 +1,502 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+	"github.com/oasisprotocol/cli/table"
+)
+
+// Kinds and sources of supply ledger entries.
+const (
+	supplyKindMint = "mint"
+	supplyKindBurn = "burn"
+
+	supplySourceProposal = "proposal"
+	supplySourceMintST   = "mintst"
+	supplySourceBurnST   = "burnst"
+	supplySourceOther    = "other"
+
+	// supplyMaxConcurrency is the maximum number of concurrently running balance queries.
+	supplyMaxConcurrency = 8
+)
+
+var (
+	supplyFromRound uint64
+
+	managestSupplyReportCmd = &cobra.Command{
+		Use:   "supply-report",
+		Short: "Show the stablecoin issuance ledger and reconcile it against the supply",
+		Long: "Walk all runtime rounds from --from-round up to the round selected by --round or --height " +
+			"(default: latest round) and list every mint and burn of the stablecoin denomination selected by " +
+			"--denom (default: native) together with its source: an executed Mint or Burn proposal, a direct mintst or burnst " +
+			"transaction or other activity (e.g. deposits and withdrawals). Each entry includes the running " +
+			"supply total.\n\n" +
+			"The supply at the first round plus all mints minus all burns is reconciled against the supply " +
+			"at the last round, computed as the sum of all account balances. A mismatch is reported as an " +
+			"error.\n\n" +
+			"Mint and Burn proposals are looked up among the proposals selected by --since-id or " +
+			"--max-proposals at the first round and all newer ones. Older proposals are only attributed " +
+			"through the vote that executed them.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg := cliConfig.Global()
+			npa := common.GetNPASelection(cfg)
+			format := common.GetTabularOutputFormat()
+
+			if npa.ParaTime == nil {
+				cobra.CheckErr("no runtime configured")
+			}
+
+			// Establish connection with the target network.
+			ctx := context.Background()
+			conn, err := connection.Connect(ctx, npa.Network)
+			cobra.CheckErr(err)
+
+			fromRound := supplyFromRound
+			if !cmd.Flags().Changed("from-round") {
+				blk, err := conn.Runtime(npa.ParaTime).GetLastRetainedBlock(ctx)
+				cobra.CheckErr(err)
+				fromRound = blk.Header.Round
+			}
+			toRound, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+			cobra.CheckErr(err)
+			if fromRound > toRound {
+				cobra.CheckErr(fmt.Errorf("from round %d is after to round %d", fromRound, toRound))
+			}
+
+			denom, err := common.GetDenomination(npa.ParaTime)
+			cobra.CheckErr(err)
+
+			report, err := buildSupplyReport(ctx, conn, npa.ParaTime, denom, fromRound, toRound, func(latestID uint32) uint32 {
+				return proposalRangeStart(cmd, latestID)
+			})
+			cobra.CheckErr(err)
+
+			switch format {
+			case common.FormatJSON, common.FormatYAML:
+				common.PrintStructured(format, report)
+			case common.FormatCSV:
+				w := csv.NewWriter(os.Stdout)
+				cobra.CheckErr(w.Write([]string{"round", "kind", "source", "proposal_id", "address", "amount", "meta", "total"}))
+				for _, entry := range report.Entries {
+					var proposalID string
+					if entry.ProposalID != nil {
+						proposalID = fmt.Sprintf("%d", *entry.ProposalID)
+					}
+					cobra.CheckErr(w.Write([]string{
+						fmt.Sprintf("%d", entry.Round),
+						entry.Kind,
+						entry.Source,
+						proposalID,
+						entry.Address.String(),
+						entry.Amount.String(),
+						entry.Meta,
+						entry.Total.String(),
+					}))
+				}
+				w.Flush()
+				cobra.CheckErr(w.Error())
+			default:
+				output := make([][]string, 0, len(report.Entries))
+				for _, entry := range report.Entries {
+					var proposalID string
+					if entry.ProposalID != nil {
+						proposalID = fmt.Sprintf("%d", *entry.ProposalID)
+					}
+					output = append(output, []string{
+						fmt.Sprintf("%d", entry.Round),
+						entry.Kind,
+						entry.Source,
+						proposalID,
+						entry.Address.String(),
+						formatSupply(npa.ParaTime, denom, entry.Amount),
+						entry.Meta,
+						formatSupply(npa.ParaTime, denom, entry.Total),
+					})
+				}
+
+				fmt.Printf("Stablecoin ledger between rounds %d and %d:\n", fromRound, toRound)
+				table := table.New()
+				table.SetHeader([]string{"Round", "Kind", "Source", "Proposal", "Address", "Amount", "Meta", "Total"})
+				table.AppendBulk(output)
+				table.Render()
+
+				fmt.Println()
+				fmt.Printf("Opening supply:  %s\n", formatSupply(npa.ParaTime, denom, report.OpeningSupply))
+				fmt.Printf("Minted:          %s\n", formatSupply(npa.ParaTime, denom, report.Minted))
+				fmt.Printf("Burned:          %s\n", formatSupply(npa.ParaTime, denom, report.Burned))
+				fmt.Printf("Expected supply: %s\n", formatSupply(npa.ParaTime, denom, report.ExpectedSupply))
+				fmt.Printf("Actual supply:   %s\n", formatSupply(npa.ParaTime, denom, report.ActualSupply))
+			}
+
+			if !report.Reconciled {
+				diff := new(big.Int).Sub(report.ActualSupply.ToBigInt(), report.ExpectedSupply.ToBigInt())
+				cobra.CheckErr(fmt.Errorf("supply mismatch: actual supply differs from the ledger by %s base units", diff))
+			}
+			if format == common.FormatTable {
+				fmt.Println("Supply reconciled successfully.")
+			}
+		},
+	}
+)
+
+// supplyEntry is a single mint or burn of the stablecoin.
+type supplyEntry struct {
+	Round      uint64            `json:"round"`
+	Kind       string            `json:"kind"`
+	Source     string            `json:"source"`
+	ProposalID *uint32           `json:"proposal_id,omitempty"`
+	Address    types.Address     `json:"address"`
+	Amount     quantity.Quantity `json:"amount"`
+	Meta       string            `json:"meta,omitempty"`
+	// Total is the running supply total after this entry.
+	Total quantity.Quantity `json:"total"`
+}
+
+// supplyReport is the stablecoin issuance ledger together with the supply reconciliation.
+type supplyReport struct {
+	Denomination   string            `json:"denomination"`
+	FromRound      uint64            `json:"from_round"`
+	ToRound        uint64            `json:"to_round"`
+	OpeningSupply  quantity.Quantity `json:"opening_supply"`
+	Minted         quantity.Quantity `json:"minted"`
+	Burned         quantity.Quantity `json:"burned"`
+	ExpectedSupply quantity.Quantity `json:"expected_supply"`
+	ActualSupply   quantity.Quantity `json:"actual_supply"`
+	Reconciled     bool              `json:"reconciled"`
+	Entries        []*supplyEntry    `json:"entries"`
+}
+
+// formatSupply formats the given amount of base units of the stablecoin denomination.
+func formatSupply(pt *config.ParaTime, denom types.Denomination, amount quantity.Quantity) string {
+	return helpers.FormatParaTimeDenomination(pt, types.NewBaseUnits(amount, denom))
+}
+
+// querySupply returns the supply of the given denomination at the given round as the sum of all
+// balances.
+func querySupply(ctx context.Context, conn connection.Connection, pt *config.ParaTime, denom types.Denomination, round uint64) (*quantity.Quantity, error) {
+	addrs, err := conn.Runtime(pt).Accounts.Addresses(ctx, round, denom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts at round %d: %w", round, err)
+	}
+
+	// Query the balances concurrently, keeping the results in order.
+	balances := make([]*accounts.AccountBalances, len(addrs))
+	errs := make([]error, len(addrs))
+	sem := make(chan struct{}, supplyMaxConcurrency)
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		i, addr := i, addr
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			balances[i], errs[i] = conn.Runtime(pt).Accounts.Balances(ctx, round, addr)
+		}()
+	}
+	wg.Wait()
+
+	supply := quantity.NewQuantity()
+	for i, addr := range addrs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("failed to query balance of %s at round %d: %w", addr, round, errs[i])
+		}
+		if balance, ok := balances[i].Balances[denom]; ok {
+			if err = supply.Add(&balance); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return supply, nil
+}
+
+// directSupplyTx is a mintst or burnst transaction.
+type directSupplyTx struct {
+	kind    string
+	address types.Address
+	amount  types.BaseUnits
+	used    bool
+}
+
+// roundDirectSupplyTxs returns the mintst and burnst transactions executed in the given round.
+func roundDirectSupplyTxs(ctx context.Context, conn connection.Connection, pt *config.ParaTime, round uint64) ([]*directSupplyTx, error) {
+	mintMethod := accounts.NewMintSTTx(nil, &accounts.MintST{}).Call.Method
+	burnMethod := accounts.NewBurnSTTx(nil, &accounts.BurnST{}).Call.Method
+
+	txs, err := roundTransactions(ctx, conn, pt, round)
+	if err != nil {
+		return nil, err
+	}
+
+	var direct []*directSupplyTx
+	for _, tx := range txs {
+		switch tx.Call.Method {
+		case mintMethod:
+			var body accounts.MintST
+			if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+				continue
+			}
+			direct = append(direct, &directSupplyTx{kind: supplyKindMint, address: body.To, amount: body.Amount})
+		case burnMethod:
+			if len(tx.AuthInfo.SignerInfo) == 0 {
+				continue
+			}
+			var body accounts.BurnST
+			if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+				continue
+			}
+			owner, err := tx.AuthInfo.SignerInfo[0].AddressSpec.Address()
+			if err != nil {
+				continue
+			}
+			direct = append(direct, &directSupplyTx{kind: supplyKindBurn, address: owner, amount: body.Amount})
+		}
+	}
+	return direct, nil
+}
+
+// supplyProposalKind returns the ledger entry kind of the given proposal, if it is a Mint or Burn
+// proposal of the given denomination.
+func supplyProposalKind(proposal *proposalInfo, denom types.Denomination) (string, bool) {
+	if proposal.Raw.Data.Address == nil {
+		return "", false
+	}
+	if amount := proposal.Raw.Data.Amount; amount != nil && amount.Denomination != denom {
+		return "", false
+	}
+	switch proposal.Raw.Action {
+	case types.Mint:
+		return supplyKindMint, true
+	case types.Burn:
+		return supplyKindBurn, true
+	default:
+		return "", false
+	}
+}
+
+// votedSupplyProposals returns the IDs of the Mint or Burn proposals of the given kind applying to
+// the given address which received a vote in the given round.
+func votedSupplyProposals(ctx context.Context, conn connection.Connection, pt *config.ParaTime, denom types.Denomination, round uint64, kind string, addr types.Address) ([]uint32, error) {
+	votes, _, err := roundVotes(ctx, conn, pt, round)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint32
+	for _, vote := range votes {
+		proposal, err := fetchProposal(ctx, conn, pt, round, vote.ProposalID)
+		if err != nil {
+			return nil, err
+		}
+		if proposal == nil {
+			continue
+		}
+		if k, ok := supplyProposalKind(proposal, denom); ok && k == kind && *proposal.Raw.Data.Address == addr {
+			ids = append(ids, proposal.ID)
+		}
+	}
+	return ids, nil
+}
+
+// buildSupplyReport builds the ledger of the given stablecoin denomination in the given (inclusive)
+// round range. Only proposals with an ID of at least rangeStart(latestID), where latestID is the
+// latest proposal at the first round, are indexed up front. Older proposals are only attributed
+// when they receive the closing vote in the round of the mint or burn.
+func buildSupplyReport(
+	ctx context.Context,
+	conn connection.Connection,
+	pt *config.ParaTime,
+	denom types.Denomination,
+	fromRound, toRound uint64,
+	rangeStart func(latestID uint32) uint32,
+) (*supplyReport, error) {
+	// The closing supply is independent of the ledger, query it in the meantime.
+	type supplyResult struct {
+		supply *quantity.Quantity
+		err    error
+	}
+	actualCh := make(chan supplyResult, 1)
+	go func() {
+		supply, err := querySupply(ctx, conn, pt, denom, toRound)
+		actualCh <- supplyResult{supply, err}
+	}()
+
+	// Collect Mint and Burn proposals which may close within the round range keyed by the address
+	// they apply to.
+	proposals := map[string]map[types.Address][]uint32{
+		supplyKindMint: make(map[types.Address][]uint32),
+		supplyKindBurn: make(map[types.Address][]uint32),
+	}
+	firstID, err := conn.Runtime(pt).Accounts.ProposalIDInfo(ctx, fromRound)
+	if err != nil {
+		return nil, err
+	}
+	latestID, err := conn.Runtime(pt).Accounts.ProposalIDInfo(ctx, toRound)
+	if err != nil {
+		return nil, err
+	}
+	for id := rangeStart(firstID); id <= latestID; id++ {
+		proposal, err := fetchProposal(ctx, conn, pt, toRound, id)
+		if err != nil {
+			return nil, err
+		}
+		if proposal == nil {
+			continue
+		}
+		if kind, ok := supplyProposalKind(proposal, denom); ok {
+			addr := *proposal.Raw.Data.Address
+			proposals[kind][addr] = append(proposals[kind][addr], proposal.ID)
+		}
+	}
+
+	opening, err := querySupply(ctx, conn, pt, denom, fromRound)
+	if err != nil {
+		return nil, err
+	}
+	report := &supplyReport{
+		Denomination:  pt.GetDenominationInfo(denom).Symbol,
+		FromRound:     fromRound,
+		ToRound:       toRound,
+		OpeningSupply: *opening,
+	}
+	total := opening.Clone()
+
+	for round := fromRound + 1; round <= toRound; round++ {
+		events, err := conn.Runtime(pt).Accounts.GetEvents(ctx, round)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query events at round %d: %w", round, err)
+		}
+
+		var direct []*directSupplyTx
+		for _, ev := range events {
+			var (
+				kind   string
+				owner  types.Address
+				amount types.BaseUnits
+			)
+			switch {
+			case ev.Mint != nil:
+				kind, owner, amount = supplyKindMint, ev.Mint.Owner, ev.Mint.Amount
+			case ev.Burn != nil:
+				kind, owner, amount = supplyKindBurn, ev.Burn.Owner, ev.Burn.Amount
+			default:
+				continue
+			}
+			if amount.Denomination != denom {
+				continue
+			}
+
+			if direct == nil {
+				if direct, err = roundDirectSupplyTxs(ctx, conn, pt, round); err != nil {
+					return nil, err
+				}
+			}
+
+			entry := &supplyEntry{
+				Round:   round,
+				Kind:    kind,
+				Source:  supplySourceOther,
+				Address: owner,
+				Amount:  amount.Amount,
+			}
+
+			// Attribute the event to a direct transaction or an executed proposal.
+			for _, tx := range direct {
+				if tx.used || tx.kind != kind || tx.address != owner || tx.amount.Denomination != denom || tx.amount.Amount.Cmp(&amount.Amount) != 0 {
+					continue
+				}
+				tx.used = true
+				entry.Source = supplySourceMintST
+				if kind == supplyKindBurn {
+					entry.Source = supplySourceBurnST
+				}
+				break
+			}
+			if entry.Source == supplySourceOther {
+				proposal, err := findClosedProposal(ctx, conn, pt, proposals[kind][owner], round)
+				if err != nil {
+					return nil, err
+				}
+				if proposal == nil {
+					// The proposal may be older than the indexed ones.
+					voted, err := votedSupplyProposals(ctx, conn, pt, denom, round, kind, owner)
+					if err != nil {
+						return nil, err
+					}
+					if proposal, err = findClosedProposal(ctx, conn, pt, voted, round); err != nil {
+						return nil, err
+					}
+				}
+				if proposal != nil {
+					entry.Source = supplySourceProposal
+					entry.ProposalID = &proposal.ID
+					entry.Meta = proposal.Content[proposalFieldMeta]
+				}
+			}
+
+			switch kind {
+			case supplyKindMint:
+				if err = report.Minted.Add(&entry.Amount); err != nil {
+					return nil, err
+				}
+				if err = total.Add(&entry.Amount); err != nil {
+					return nil, err
+				}
+			case supplyKindBurn:
+				if err = report.Burned.Add(&entry.Amount); err != nil {
+					return nil, err
+				}
+				if err = total.Sub(&entry.Amount); err != nil {
+					return nil, fmt.Errorf("ledger inconsistent: burn at round %d exceeds the running supply total", round)
+				}
+			}
+			entry.Total = *total.Clone()
+			report.Entries = append(report.Entries, entry)
+		}
+	}
+
+	actual := <-actualCh
+	if actual.err != nil {
+		return nil, actual.err
+	}
+	report.ActualSupply = *actual.supply
+	report.ExpectedSupply = *total
+	report.Reconciled = report.ActualSupply.Cmp(&report.ExpectedSupply) == 0
+
+	return report, nil
+}
+
+func init() {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.Uint64Var(&supplyFromRound, "from-round", 0, "first round of the ledger (default: last retained round)")
+
+	managestSupplyReportCmd.Flags().AddFlagSet(common.SelectorNPFlags)
+	managestSupplyReportCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestSupplyReportCmd.Flags().AddFlagSet(common.DenominationFlag)
+	managestSupplyReportCmd.Flags().AddFlagSet(newProposalRangeFlags())
+	managestSupplyReportCmd.Flags().AddFlagSet(common.TabularFormatFlag)
+	managestSupplyReportCmd.Flags().AddFlagSet(f)
+
+	managestCmd.AddCommand(managestSupplyReportCmd)
+}
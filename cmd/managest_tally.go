@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+	"github.com/oasisprotocol/cli/table"
+)
+
+// proposalTally is the quorum progress of a single proposal.
+type proposalTally struct {
+	// Quorum is the percentage of the voter team required to vote yes.
+	Quorum uint64
+	// TeamSize is the number of members of the voter team.
+	TeamSize uint64
+
+	Yes     uint64
+	No      uint64
+	Abstain uint64
+}
+
+// newProposalTally computes the tally from proposal results.
+func newProposalTally(results map[string]uint64, quorum, teamSize uint64) *proposalTally {
+	return &proposalTally{
+		Quorum:   quorum,
+		TeamSize: teamSize,
+		Yes:      voteCount(results, "yes"),
+		No:       voteCount(results, "no"),
+		Abstain:  voteCount(results, "abstain"),
+	}
+}
+
+// Required returns the number of yes votes required to reach the quorum.
+func (t *proposalTally) Required() uint64 {
+	return (t.TeamSize*t.Quorum + 99) / 100
+}
+
+// NoVotesRequired returns true, if the quorum is 0% and proposals pass without any yes votes.
+func (t *proposalTally) NoVotesRequired() bool {
+	return t.Quorum == 0
+}
+
+// Reachable returns true, if the quorum can be reached by the voter team at all. An empty voter
+// team can only pass proposals that require no votes.
+func (t *proposalTally) Reachable() bool {
+	return t.NoVotesRequired() || (t.TeamSize > 0 && t.Required() <= t.TeamSize)
+}
+
+// Missing returns the number of yes votes still needed to reach the quorum.
+func (t *proposalTally) Missing() uint64 {
+	if required := t.Required(); t.Yes < required {
+		return required - t.Yes
+	}
+	return 0
+}
+
+// NotVoted returns the number of voter team members that have not voted yet.
+func (t *proposalTally) NotVoted() uint64 {
+	if voted := t.Yes + t.No + t.Abstain; voted < t.TeamSize {
+		return t.TeamSize - voted
+	}
+	return 0
+}
+
+// CanPass returns true, if the remaining voters can still bring the proposal to the quorum.
+func (t *proposalTally) CanPass() bool {
+	return t.Missing() <= t.NotVoted()
+}
+
+// Percent returns the given number of votes as a percentage of the voter team.
+func (t *proposalTally) Percent(votes uint64) float64 {
+	if t.TeamSize == 0 {
+		return 0
+	}
+	return float64(votes) * 100 / float64(t.TeamSize)
+}
+
+var managestTallyCmd = &cobra.Command{
+	Use:   "tally <proposal-id>",
+	Short: "Show quorum progress and missing voters of a proposal",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+
+		if npa.ParaTime == nil {
+			cobra.CheckErr("no runtime configured")
+		}
+
+		proposalID, err := strconv.ParseUint(args[0], 10, 32)
+		cobra.CheckErr(err)
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+		cobra.CheckErr(err)
+
+		proposal, err := fetchProposal(ctx, conn, npa.ParaTime, round, uint32(proposalID))
+		cobra.CheckErr(err)
+		if proposal == nil {
+			cobra.CheckErr(fmt.Errorf("proposal %d does not exist", proposalID))
+		}
+
+		quorum, err := conn.Runtime(npa.ParaTime).Accounts.Quorums(ctx, round, proposal.Raw.Action)
+		cobra.CheckErr(err)
+
+		voterRole, err := voterRoleForAction(proposal.Raw.Action)
+		cobra.CheckErr(err)
+		voters, err := conn.Runtime(npa.ParaTime).Accounts.RolesTeam(ctx, round, voterRole)
+		cobra.CheckErr(err)
+
+		tally := newProposalTally(proposal.Results, uint64(quorum), uint64(len(voters)))
+
+		fmt.Printf("Proposal ID: %d\n", proposal.ID)
+		fmt.Printf("Action:      %s\n", proposal.ActionName)
+		fmt.Printf("State:       %s\n", proposal.State)
+		fmt.Printf("Voter role:  %s (%d members)\n", voterRole.String(), tally.TeamSize)
+		fmt.Printf("Quorum:      %d%% (%d yes votes required)\n", tally.Quorum, tally.Required())
+		fmt.Println()
+		fmt.Printf("Yes:         %d (%.1f%%)\n", tally.Yes, tally.Percent(tally.Yes))
+		fmt.Printf("No:          %d (%.1f%%)\n", tally.No, tally.Percent(tally.No))
+		fmt.Printf("Abstain:     %d (%.1f%%)\n", tally.Abstain, tally.Percent(tally.Abstain))
+		fmt.Printf("Not voted:   %d (%.1f%%)\n", tally.NotVoted(), tally.Percent(tally.NotVoted()))
+		fmt.Println()
+		switch {
+		case tally.NoVotesRequired():
+			fmt.Printf("Quorum is 0%%. No yes votes are required.\n")
+		case !tally.Reachable():
+			fmt.Printf("The %s team has %d member(s). Quorum cannot be reached.\n", voterRole.String(), tally.TeamSize)
+		case tally.Missing() == 0:
+			fmt.Printf("Quorum reached.\n")
+		case tally.CanPass():
+			fmt.Printf("Missing %d yes vote(s) to reach the quorum.\n", tally.Missing())
+		default:
+			fmt.Printf("Missing %d yes vote(s), but only %d voter(s) left. Quorum can no longer be reached.\n", tally.Missing(), tally.NotVoted())
+		}
+
+		if tally.NotVoted() == 0 {
+			return
+		}
+
+		// Individual votes are not part of the proposal state, so replay the recent vote
+		// transactions.
+		votes, err := scanProposalVotes(ctx, conn, npa.ParaTime, proposal, round)
+		cobra.CheckErr(err)
+
+		var (
+			output  [][]string
+			unknown int
+		)
+		for _, voter := range voters {
+			status := "not voted"
+			switch option, known := votes.lookup(voter); {
+			case option != "":
+				continue
+			case !known:
+				status = "unknown"
+				unknown++
+			}
+			output = append(output, []string{
+				voter.String(),
+				common.FindAccountName(cfg, voter.String()),
+				status,
+			})
+		}
+		sort.Slice(output, func(i, j int) bool {
+			return output[i][0] < output[j][0]
+		})
+
+		fmt.Println()
+		fmt.Println("Voters that have not voted yet:")
+		table := table.New()
+		table.SetHeader([]string{"Address", "Name", "Status"})
+		table.AppendBulk(output)
+		table.Render()
+
+		if unknown > 0 {
+			fmt.Printf("\nThe votes of %d voter(s) could not be determined (%s).\n", unknown, votes.hint())
+		}
+	},
+}
+
+func init() {
+	managestTallyCmd.Flags().AddFlagSet(common.SelectorFlags)
+	managestTallyCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestTallyCmd.Flags().AddFlagSet(newVoteScanFlags())
+
+	managestCmd.AddCommand(managestTallyCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposalTally(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		results   map[string]uint64
+		quorum    uint64
+		teamSize  uint64
+		required  uint64
+		missing   uint64
+		notVoted  uint64
+		canPass   bool
+		reachable bool
+	}{
+		{results: map[string]uint64{}, quorum: 50, teamSize: 4, required: 2, missing: 2, notVoted: 4, canPass: true, reachable: true},
+		{results: map[string]uint64{"Yes": 1, "No": 1}, quorum: 67, teamSize: 3, required: 3, missing: 2, notVoted: 1, canPass: false, reachable: true},
+		{results: map[string]uint64{"yes": 2, "abstain": 1}, quorum: 60, teamSize: 5, required: 3, missing: 1, notVoted: 2, canPass: true, reachable: true},
+		{results: map[string]uint64{"YES": 3}, quorum: 100, teamSize: 3, required: 3, missing: 0, notVoted: 0, canPass: true, reachable: true},
+		{results: map[string]uint64{}, quorum: 0, teamSize: 3, required: 0, missing: 0, notVoted: 3, canPass: true, reachable: true},
+		{results: map[string]uint64{}, quorum: 120, teamSize: 3, required: 4, missing: 4, notVoted: 3, canPass: false, reachable: false},
+		{results: map[string]uint64{"Yes": 1}, quorum: 50, teamSize: 0, required: 0, missing: 0, notVoted: 0, canPass: true, reachable: false},
+	} {
+		tally := newProposalTally(tc.results, tc.quorum, tc.teamSize)
+		require.Equal(tc.required, tally.Required(), "required votes (%v)", tc.results)
+		require.Equal(tc.missing, tally.Missing(), "missing votes (%v)", tc.results)
+		require.Equal(tc.notVoted, tally.NotVoted(), "not voted (%v)", tc.results)
+		require.Equal(tc.canPass, tally.CanPass(), "can pass (%v)", tc.results)
+		require.Equal(tc.reachable, tally.Reachable(), "reachable (quorum %d, team size %d)", tc.quorum, tc.teamSize)
+	}
+}
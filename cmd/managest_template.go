@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+// proposalFieldPlaceholder returns the placeholder value of the given field in proposal templates.
+func proposalFieldPlaceholder(field string) interface{} {
+	switch field {
+	case proposalFieldAddress:
+		return "<account name, address book name or address>"
+	case proposalFieldAmount:
+		return "0.0"
+	case proposalFieldMeta:
+		return ""
+	case proposalFieldRole:
+		return types.MintProposer.String()
+	default:
+		// Quorums.
+		return 50
+	}
+}
+
+// schema returns the JSON schema of the field values.
+func (spec *proposalFieldSpec) schema() map[string]interface{} {
+	schema := map[string]interface{}{"type": "string"}
+	if spec.description != "" {
+		schema["description"] = spec.description
+	}
+	if spec.pattern != nil {
+		schema["pattern"] = spec.pattern.String()
+	}
+	if spec.enum != nil {
+		schema["enum"] = spec.enum()
+	}
+	if spec.quorum {
+		schema["type"] = "integer"
+		schema["minimum"] = minQuorum
+		schema["maximum"] = maxQuorum
+	}
+	return schema
+}
+
+// proposalSchema returns the JSON schema of proposal files.
+func proposalSchema() map[string]interface{} {
+	var variants []interface{}
+	for action := types.SetRoles; action <= types.Config; action++ {
+		rules, ok := proposalFieldRules[action]
+		if !ok {
+			continue
+		}
+
+		properties := make(map[string]interface{})
+		for _, field := range append(append([]string{}, rules.required...), rules.optional...) {
+			properties[field] = proposalFieldSpecs[field].schema()
+		}
+		data := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if len(rules.required) > 0 {
+			data["required"] = rules.required
+		}
+		if rules.minFields > 0 {
+			data["minProperties"] = rules.minFields
+		}
+
+		variants = append(variants, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"action": map[string]interface{}{"const": action.String()},
+				"data":   data,
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                "Stablecoin management proposal",
+		"type":                 "object",
+		"required":             []string{"action", "data"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"action": map[string]interface{}{"type": "string"},
+			"data":   map[string]interface{}{"type": "object"},
+		},
+		"oneOf": variants,
+	}
+}
+
+var (
+	managestProposalTemplateCmd = &cobra.Command{
+		Use:   "proposal-template <action>",
+		Short: "Print a proposal JSON skeleton for the given action",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := types.ActionFromString(args[0])
+			cobra.CheckErr(err)
+
+			rules, ok := proposalFieldRules[action]
+			if !ok {
+				cobra.CheckErr(fmt.Errorf("action '%s' cannot be proposed", action))
+			}
+
+			data := make(map[string]interface{})
+			for _, field := range append(append([]string{}, rules.required...), rules.optional...) {
+				data[field] = proposalFieldPlaceholder(field)
+			}
+
+			formatted, err := common.PrettyJSONMarshal(struct {
+				Action string                 `json:"action"`
+				Data   map[string]interface{} `json:"data"`
+			}{
+				Action: action.String(),
+				Data:   data,
+			})
+			cobra.CheckErr(err)
+			fmt.Println(string(formatted))
+		},
+	}
+
+	managestProposalSchemaCmd = &cobra.Command{
+		Use:   "proposal-schema",
+		Short: "Print the JSON schema of proposal files",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			formatted, err := common.PrettyJSONMarshal(proposalSchema())
+			cobra.CheckErr(err)
+			fmt.Println(string(formatted))
+		},
+	}
+
+	managestValidateCmd = &cobra.Command{
+		Use:   "validate <proposal.json>",
+		Short: "Validate a proposal JSON file offline",
+		Long: "Validate a proposal JSON file against the proposal schema (see proposal-schema), resolve addresses " +
+			"using the wallet and address book and parse amounts using the runtime denomination. No network " +
+			"access is required.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg := cliConfig.Global()
+			npa := common.GetNPASelection(cfg)
+
+			if npa.ParaTime == nil {
+				cobra.CheckErr("no runtime configured")
+			}
+
+			rawProposal, err := ioutil.ReadFile(args[0])
+			cobra.CheckErr(err)
+
+			action, dataStr, err := parseProposal(rawProposal)
+			cobra.CheckErr(err)
+
+			if errs := proposalFieldErrors(action, dataStr); len(errs) > 0 {
+				fmt.Printf("Proposal is invalid:\n")
+				for _, err := range errs {
+					fmt.Printf("  - %s\n", err)
+				}
+				cobra.CheckErr(fmt.Errorf("%d problem(s) found", len(errs)))
+			}
+
+			data, err := newProposalData(npa, action, dataStr)
+			cobra.CheckErr(err)
+
+			content := &accounts.ProposalContent{
+				Action: action,
+				Data:   *data,
+			}
+			contentStr, err := content.String()
+			cobra.CheckErr(err)
+
+			fmt.Printf("Proposal is valid.\n")
+			fmt.Printf("Action: %s\n", action)
+			fmt.Printf("Content:\n")
+			for _, key := range sortedKeys(contentStr) {
+				fmt.Printf("    %s: %s\n", key, contentStr[key])
+			}
+			if data.Address != nil {
+				if name := common.FindAccountName(cfg, data.Address.String()); name != "" {
+					fmt.Printf("Address %s is known as '%s'.\n", data.Address, name)
+				}
+			}
+		},
+	}
+)
+
+func init() {
+	managestValidateCmd.Flags().AddFlagSet(common.SelectorFlags)
+
+	managestCmd.AddCommand(managestProposalTemplateCmd)
+	managestCmd.AddCommand(managestProposalSchemaCmd)
+	managestCmd.AddCommand(managestValidateCmd)
+}
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+	"github.com/oasisprotocol/cli/table"
+	"github.com/oasisprotocol/cli/wallet"
+)
+
+// voteBatchRow is a single vote of a vote batch file.
+type voteBatchRow struct {
+	// Line is the line (CSV) or the element index (JSON) of the row, starting at 1.
+	Line       int
+	ProposalID uint32
+	Option     string
+	Account    string
+
+	vote     types.Vote
+	skipErr  error
+	forceErr error
+	round    uint64
+	err      error
+}
+
+// voteBatchEntry is the JSON representation of a vote batch row.
+type voteBatchEntry struct {
+	ProposalID uint32 `json:"proposal_id"`
+	Option     string `json:"option"`
+	Account    string `json:"account,omitempty"`
+}
+
+// parseVoteBatch parses a vote batch file in either JSON or CSV format. Rows without an account
+// use the given default account. A CSV header row is skipped.
+func parseVoteBatch(raw []byte, isJSON bool, defaultAccount string) ([]*voteBatchRow, error) {
+	var rows []*voteBatchRow
+	if isJSON {
+		var entries []voteBatchEntry
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&entries); err != nil {
+			return nil, fmt.Errorf("malformed vote batch: %w", err)
+		}
+		for i, entry := range entries {
+			rows = append(rows, &voteBatchRow{
+				Line:       i + 1,
+				ProposalID: entry.ProposalID,
+				Option:     entry.Option,
+				Account:    entry.Account,
+			})
+		}
+	} else {
+		r := csv.NewReader(bytes.NewReader(raw))
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+		r.Comment = '#'
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("malformed vote batch: %w", err)
+			}
+			line, _ := r.FieldPos(0)
+			if len(record) < 2 || len(record) > 3 {
+				return nil, fmt.Errorf("line %d: expected 2 or 3 fields (proposal ID, option[, account]), got %d", line, len(record))
+			}
+
+			id, err := strconv.ParseUint(strings.TrimSpace(record[0]), 10, 32)
+			if err != nil {
+				if len(rows) == 0 && line == 1 {
+					// Header row.
+					continue
+				}
+				return nil, fmt.Errorf("line %d: bad proposal ID: %w", line, err)
+			}
+			row := &voteBatchRow{
+				Line:       line,
+				ProposalID: uint32(id),
+				Option:     strings.TrimSpace(record[1]),
+			}
+			if len(record) == 3 {
+				row.Account = strings.TrimSpace(record[2])
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("vote batch is empty")
+	}
+	type voteKey struct {
+		proposalID uint32
+		account    string
+	}
+	seen := make(map[voteKey]int)
+	for _, row := range rows {
+		if row.Account == "" {
+			row.Account = defaultAccount
+		}
+		if row.Account == "" {
+			return nil, fmt.Errorf("line %d: no account given and no default account configured", row.Line)
+		}
+		key := voteKey{row.ProposalID, row.Account}
+		if line, ok := seen[key]; ok {
+			return nil, fmt.Errorf("line %d: account '%s' already votes on proposal %d on line %d", row.Line, row.Account, row.ProposalID, line)
+		}
+		seen[key] = row.Line
+
+		var err error
+		row.Option = strings.ToLower(row.Option)
+		if row.vote, err = types.StringToVote(row.Option); err != nil {
+			return nil, fmt.Errorf("line %d: bad option '%s': %w", row.Line, row.Option, err)
+		}
+	}
+	return rows, nil
+}
+
+// renderTable renders a table with the given header and rows.
+func renderTable(header []string, rows [][]string) {
+	table := table.New()
+	table.SetHeader(header)
+	table.AppendBulk(rows)
+	table.Render()
+}
+
+var managestVoteBatchCmd = &cobra.Command{
+	Use:   "vote-batch <votes.csv|votes.json>",
+	Short: "Vote on multiple proposals from a CSV or JSON file",
+	Long: "Vote on multiple proposals using one or more wallet accounts. CSV files contain rows of " +
+		"proposal ID, option and an optional account name. JSON files contain an array of objects with " +
+		"proposal_id, option and optional account fields. Rows without an account use the selected account.\n\n" +
+		"Each involved account is unlocked once and all votes are confirmed together. Rows failing the " +
+		"role and proposal checks are skipped unless --force is given.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+		txCfg := common.GetTransactionConfig()
+
+		if npa.ParaTime == nil {
+			cobra.CheckErr("no runtime configured")
+		}
+		if txCfg.Offline {
+			cobra.CheckErr("vote-batch is not available in offline mode")
+		}
+
+		raw, err := ioutil.ReadFile(args[0])
+		cobra.CheckErr(err)
+		rows, err := parseVoteBatch(raw, strings.EqualFold(filepath.Ext(args[0]), ".json"), npa.AccountName)
+		cobra.CheckErr(err)
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		// Resolve account addresses and run the pre-flight checks, scanning each proposal for votes
+		// only once.
+		var accountNames []string
+		votes := make(voteScanCache)
+		addrs := make(map[string]types.Address)
+		for _, row := range rows {
+			if _, ok := addrs[row.Account]; !ok {
+				acfg, exists := cfg.Wallet.All[row.Account]
+				if !exists {
+					cobra.CheckErr(fmt.Errorf("line %d: account '%s' does not exist in the wallet", row.Line, row.Account))
+				}
+				addr, err := helpers.ResolveAddress(npa.Network, acfg.Address)
+				cobra.CheckErr(err)
+				addrs[row.Account] = *addr
+				accountNames = append(accountNames, row.Account)
+			}
+
+			err := preflightVoteAs(ctx, conn, npa, row.Account, addrs[row.Account], row.ProposalID, votes)
+			switch {
+			case err == nil:
+			case common.IsForce():
+				row.forceErr = err
+			default:
+				row.skipErr = err
+			}
+		}
+
+		// Show the consolidated confirmation table.
+		var pending, forced int
+		output := make([][]string, 0, len(rows))
+		for _, row := range rows {
+			check := "ok"
+			switch {
+			case row.skipErr != nil:
+				check = "skip: " + row.skipErr.Error()
+			case row.forceErr != nil:
+				check = "forced: " + row.forceErr.Error()
+				pending++
+				forced++
+			default:
+				pending++
+			}
+			output = append(output, []string{
+				fmt.Sprintf("%d", row.Line),
+				fmt.Sprintf("%d", row.ProposalID),
+				strings.ToUpper(row.Option),
+				row.Account,
+				check,
+			})
+		}
+		renderTable([]string{"Line", "Proposal", "Option", "Account", "Check"}, output)
+		if forced > 0 {
+			fmt.Printf("Warning: %d vote(s) failed the pre-flight checks\nProceeding by force as requested\n", forced)
+		}
+
+		if pending == 0 {
+			cobra.CheckErr("no votes left to submit")
+		}
+		common.Confirm(fmt.Sprintf("Sign and submit %d vote(s) using %d account(s)?", pending, len(accountNames)), "signing aborted")
+
+		// Unlock each involved account once and sequence nonces locally, taking transactions still
+		// pending from earlier invocations into account.
+		nonceLayer := common.NonceLayer(npa)
+		wallets := make(map[string]wallet.Account)
+		nonces := make(map[string]uint64)
+		for _, name := range accountNames {
+			fmt.Printf("Account '%s':\n", name)
+			acc := common.LoadAccount(cfg, name)
+			nonce, err := common.NextNonce(ctx, npa, conn, nonceLayer, acc.Address())
+			cobra.CheckErr(err)
+
+			wallets[name] = acc
+			nonces[name] = nonce
+		}
+
+		var failed int
+		for _, row := range rows {
+			if row.skipErr != nil {
+				continue
+			}
+
+			tx := accounts.NewVoteSTTx(nil, &accounts.VoteProposal{
+				ID:     row.ProposalID,
+				Option: row.vote,
+			})
+			sigTx, meta, err := common.SignParaTimeTransactionWithNonce(ctx, npa, wallets[row.Account], conn, tx, nonces[row.Account])
+			if err == nil {
+				fmt.Printf("Submitting vote on proposal %d by '%s'...\n", row.ProposalID, row.Account)
+				row.round, err = common.SubmitParaTimeTransaction(ctx, npa.ParaTime, conn, sigTx, meta, nil)
+			}
+			if err != nil {
+				row.err = err
+				failed++
+
+				// The nonce is only consumed if the transaction made it into a block.
+				if row.round == 0 {
+					continue
+				}
+			}
+			common.RecordTrackedNonce(npa, nonceLayer, addrs[row.Account], nonces[row.Account])
+			nonces[row.Account]++
+		}
+
+		// Report the outcome of each row.
+		output = make([][]string, 0, len(rows))
+		for _, row := range rows {
+			var result, round string
+			switch {
+			case row.skipErr != nil:
+				result = "skipped"
+			case row.err != nil:
+				result = "failed: " + row.err.Error()
+			default:
+				result = "ok"
+			}
+			if row.round != 0 {
+				round = fmt.Sprintf("%d", row.round)
+			}
+			output = append(output, []string{
+				fmt.Sprintf("%d", row.Line),
+				fmt.Sprintf("%d", row.ProposalID),
+				strings.ToUpper(row.Option),
+				row.Account,
+				round,
+				result,
+			})
+		}
+		fmt.Println()
+		renderTable([]string{"Line", "Proposal", "Option", "Account", "Round", "Result"}, output)
+
+		if failed > 0 {
+			cobra.CheckErr(fmt.Errorf("%d of %d vote(s) failed", failed, pending))
+		}
+	},
+}
+
+func init() {
+	managestVoteBatchCmd.Flags().AddFlagSet(common.SelectorFlags)
+	managestVoteBatchCmd.Flags().AddFlagSet(common.TransactionFlags)
+	managestVoteBatchCmd.Flags().AddFlagSet(common.ForceFlag)
+	managestVoteBatchCmd.Flags().AddFlagSet(newVoteScanFlags())
+
+	managestCmd.AddCommand(managestVoteBatchCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestParseVoteBatch(t *testing.T) {
+	require := require.New(t)
+
+	rows, err := parseVoteBatch([]byte("proposal_id,option,account\n1,YES\n2, no ,bob\n"), false, "alice")
+	require.NoError(err)
+	require.Len(rows, 2)
+	require.EqualValues(1, rows[0].ProposalID)
+	require.Equal("yes", rows[0].Option)
+	require.Equal("alice", rows[0].Account)
+	require.Equal(2, rows[0].Line)
+	require.EqualValues(2, rows[1].ProposalID)
+	require.Equal("no", rows[1].Option)
+	require.Equal("bob", rows[1].Account)
+
+	rows, err = parseVoteBatch([]byte(`[{"proposal_id": 3, "option": "abstain", "account": "bob"}, {"proposal_id": 4, "option": "yes"}]`), true, "alice")
+	require.NoError(err)
+	require.Len(rows, 2)
+	require.Equal("bob", rows[0].Account)
+	require.Equal("alice", rows[1].Account)
+	require.Equal(2, rows[1].Line)
+
+	_, err = parseVoteBatch([]byte("1,yes\nx,no\n"), false, "alice")
+	require.Error(err, "bad proposal ID after the first line")
+	_, err = parseVoteBatch([]byte("1\n"), false, "alice")
+	require.Error(err, "missing option")
+	_, err = parseVoteBatch([]byte("1,yes\n"), false, "")
+	require.Error(err, "missing account")
+	_, err = parseVoteBatch([]byte(`[{"proposal_id": 1, "option": "yes", "extra": 1}]`), true, "alice")
+	require.Error(err, "unknown field")
+	_, err = parseVoteBatch([]byte("[]"), true, "alice")
+	require.Error(err, "empty batch")
+	_, err = parseVoteBatch([]byte("1,yes\n2,yes\n1,no,alice\n"), false, "alice")
+	require.ErrorContains(err, "line 3: account 'alice' already votes on proposal 1 on line 1")
+	_, err = parseVoteBatch([]byte("1,yes,bob\n1,yes,alice\n"), false, "alice")
+	require.NoError(err, "same proposal, different accounts")
+}
+
+func TestProposalVotesLookup(t *testing.T) {
+	require := require.New(t)
+
+	alice := types.NewAddressForModule("test", []byte("alice"))
+	bob := types.NewAddressForModule("test", []byte("bob"))
+
+	pv := &proposalVotes{
+		Votes:     map[types.Address]string{alice: "yes"},
+		Encrypted: map[types.Address]bool{bob: true},
+	}
+	option, known := pv.lookup(alice)
+	require.Equal("yes", option)
+	require.True(known)
+	_, known = pv.lookup(bob)
+	require.False(known, "lookback not covering the whole proposal")
+
+	pv.Covered = true
+	_, known = pv.lookup(bob)
+	require.False(known, "encrypted transactions of the account may be votes")
+
+	pv.Complete = true
+	option, known = pv.lookup(bob)
+	require.Empty(option)
+	require.True(known, "all counted votes found")
+}
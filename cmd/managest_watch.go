@@ -0,0 +1,432 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+// Kinds of watched events.
+const (
+	watchEventProposalCreated  = "proposal-created"
+	watchEventVoteCast         = "vote-cast"
+	watchEventProposalExecuted = "proposal-executed"
+	watchEventProposalRejected = "proposal-rejected"
+	watchEventProposalPrefix   = "proposal-"
+	watchEventRoleChanged      = "role-changed"
+	watchEventQuorumChanged    = "quorum-changed"
+	watchEventMinted           = "minted"
+	watchEventBurned           = "burned"
+)
+
+var (
+	watchAction     string
+	watchProposalID uint32
+	watchJSON       bool
+
+	managestWatchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Watch proposal lifecycle, role and quorum changes as they happen",
+		Long: "Watch runtime blocks and print proposal-created, vote-cast, proposal-executed, proposal-rejected, " +
+			"role-changed, quorum-changed, minted and burned events. Minted and burned events are decoded from the " +
+			"block events, vote-cast events from the block transactions. Proposal state, role teams and quorums " +
+			"are compared between every pair of consecutive rounds, starting at the first streamed block or at the " +
+			"round selected by --round or --height, in which case all rounds since then are replayed first. " +
+			"Proposals closed in a state other than executed or rejected are reported as proposal-<state>.\n\n" +
+			"Open proposals older than --since-id or --max-proposals are only tracked once they receive a vote.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg := cliConfig.Global()
+			npa := common.GetNPASelection(cfg)
+
+			if npa.ParaTime == nil {
+				cobra.CheckErr("no runtime configured")
+			}
+
+			var action *types.Action
+			if watchAction != "" {
+				a, err := types.ActionFromString(watchAction)
+				cobra.CheckErr(err)
+				action = &a
+			}
+			var proposalID *uint32
+			if cmd.Flags().Changed("proposal-id") {
+				proposalID = &watchProposalID
+			}
+
+			// Establish connection with the target network.
+			ctx := context.Background()
+			conn, err := connection.Connect(ctx, npa.Network)
+			cobra.CheckErr(err)
+
+			rt := conn.Runtime(npa.ParaTime)
+			decoders := []client.EventDecoder{rt.Accounts}
+			ch, err := rt.WatchEvents(ctx, decoders, false)
+			cobra.CheckErr(err)
+
+			var w *proposalWatcher
+			startWatcher := func(round uint64) {
+				// Round 0 has no predecessor, its state serves as the snapshot instead.
+				if round == 0 {
+					round = 1
+				}
+				w, err = newProposalWatcher(ctx, conn, npa, round-1, func(latestID uint32) uint32 {
+					return proposalRangeStart(cmd, latestID)
+				})
+				cobra.CheckErr(err)
+
+				if !watchJSON {
+					fmt.Printf("Watching %s on %s from round %d...\n", npa.ParaTimeName, npa.NetworkName, round)
+				}
+			}
+			if cmd.Flags().Changed("round") || cmd.Flags().Changed("height") {
+				round, err := common.GetActualRound(ctx, conn, npa.ParaTime)
+				cobra.CheckErr(err)
+				startWatcher(round)
+			}
+
+			for bev := range ch {
+				if w == nil {
+					// Take the snapshot right before the first streamed block so that no
+					// changes are lost between the snapshot and the subscription.
+					startWatcher(bev.Round)
+				}
+
+				// Catch up on any rounds the stream skipped or that are replayed.
+				for round := w.round + 1; round < bev.Round; round++ {
+					decoded, err := rt.GetEvents(ctx, round, decoders, false)
+					cobra.CheckErr(err)
+					events, err := w.update(ctx, round, decoded)
+					cobra.CheckErr(err)
+					printWatchEvents(events, action, proposalID)
+				}
+
+				events, err := w.update(ctx, bev.Round, bev.Events)
+				cobra.CheckErr(err)
+				printWatchEvents(events, action, proposalID)
+			}
+			cobra.CheckErr("event stream closed")
+		},
+	}
+)
+
+// watchEvent is a proposal lifecycle event.
+type watchEvent struct {
+	Round      uint64  `json:"round"`
+	Kind       string  `json:"kind"`
+	ProposalID *uint32 `json:"proposal_id,omitempty"`
+	Action     string  `json:"action,omitempty"`
+	Address    string  `json:"address,omitempty"`
+	Details    string  `json:"details,omitempty"`
+
+	action types.Action
+}
+
+// printWatchEvents prints the given events matching the optional action and proposal filters.
+func printWatchEvents(events []*watchEvent, action *types.Action, proposalID *uint32) {
+	for _, ev := range events {
+		if action != nil && ev.action != *action {
+			continue
+		}
+		if proposalID != nil && (ev.ProposalID == nil || *ev.ProposalID != *proposalID) {
+			continue
+		}
+		ev.print(watchJSON)
+	}
+}
+
+func (ev *watchEvent) print(asJSON bool) {
+	if asJSON {
+		line, err := json.Marshal(ev)
+		cobra.CheckErr(err)
+		fmt.Println(string(line))
+		return
+	}
+
+	parts := []string{fmt.Sprintf("[round %d] %s", ev.Round, ev.Kind)}
+	if ev.ProposalID != nil {
+		parts = append(parts, fmt.Sprintf("#%d", *ev.ProposalID))
+	}
+	if ev.Action != "" {
+		parts = append(parts, ev.Action)
+	}
+	if ev.Address != "" {
+		if name := common.FindAccountName(cliConfig.Global(), ev.Address); name != "" {
+			parts = append(parts, fmt.Sprintf("%s (%s)", ev.Address, name))
+		} else {
+			parts = append(parts, ev.Address)
+		}
+	}
+	if ev.Details != "" {
+		parts = append(parts, ev.Details)
+	}
+	fmt.Println(strings.Join(parts, " "))
+}
+
+// proposalWatcher tracks proposals, role teams and quorums between rounds.
+type proposalWatcher struct {
+	conn connection.Connection
+	npa  *common.NPASelection
+
+	round     uint64
+	latestID  uint32
+	proposals map[uint32]*proposalInfo
+	teams     map[types.Role][]types.Address
+	quorums   map[types.Action]uint64
+}
+
+// newProposalWatcher creates a watcher with a snapshot of the state at the given round. Only open
+// proposals with an ID of at least rangeStart(latestID) are tracked initially, older ones are
+// tracked once they receive a vote.
+func newProposalWatcher(
+	ctx context.Context,
+	conn connection.Connection,
+	npa *common.NPASelection,
+	round uint64,
+	rangeStart func(latestID uint32) uint32,
+) (*proposalWatcher, error) {
+	var err error
+	w := &proposalWatcher{
+		conn:      conn,
+		npa:       npa,
+		round:     round,
+		proposals: make(map[uint32]*proposalInfo),
+	}
+	if w.latestID, err = conn.Runtime(npa.ParaTime).Accounts.ProposalIDInfo(ctx, round); err != nil {
+		return nil, err
+	}
+	if w.teams, err = queryRoleTeams(ctx, conn, npa.ParaTime, round); err != nil {
+		return nil, err
+	}
+	if w.quorums, err = queryQuorums(ctx, conn, npa.ParaTime, round); err != nil {
+		return nil, err
+	}
+
+	// Track currently open proposals so that their outcome is reported.
+	for id := int64(w.latestID); id >= int64(rangeStart(w.latestID)); id-- {
+		proposal, err := fetchProposal(ctx, conn, npa.ParaTime, round, uint32(id))
+		if err != nil {
+			return nil, err
+		}
+		if proposal != nil && proposal.isOpen() {
+			w.proposals[proposal.ID] = proposal
+		}
+	}
+
+	return w, nil
+}
+
+// update decodes the given block events and compares the state at the given round with the
+// previously seen state. The resulting events are returned.
+func (w *proposalWatcher) update(ctx context.Context, round uint64, decoded []client.DecodedEvent) ([]*watchEvent, error) {
+	if round <= w.round {
+		return nil, nil
+	}
+	w.round = round
+
+	pt := w.npa.ParaTime
+	rt := w.conn.Runtime(pt)
+	var events []*watchEvent
+
+	// New proposals.
+	latestID, err := rt.Accounts.ProposalIDInfo(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	for id := w.latestID + 1; id <= latestID; id++ {
+		proposal, err := fetchProposal(ctx, w.conn, pt, round, id)
+		if err != nil {
+			return nil, err
+		}
+		if proposal == nil {
+			continue
+		}
+		events = append(events, w.proposalEvent(round, watchEventProposalCreated, proposal, proposal.Submitter.String(), formatKeyValues(proposal.Content)))
+		if proposal.isOpen() {
+			w.proposals[proposal.ID] = proposal
+		} else {
+			// Proposals may be executed in the same round they were submitted in.
+			events = append(events, w.closedEvent(round, proposal))
+		}
+	}
+	w.latestID = latestID
+
+	// Votes.
+	votes, _, err := roundVotes(ctx, w.conn, pt, round)
+	if err != nil {
+		return nil, err
+	}
+	for _, vote := range votes {
+		proposal := w.proposals[vote.ProposalID]
+		if proposal == nil {
+			if proposal, err = fetchProposal(ctx, w.conn, pt, round, vote.ProposalID); err != nil || proposal == nil {
+				continue
+			}
+			// Start tracking proposals older than the initially tracked ones, so that their
+			// outcome is reported as well.
+			if before, err := fetchProposal(ctx, w.conn, pt, round-1, vote.ProposalID); err == nil && before != nil && before.isOpen() {
+				w.proposals[proposal.ID] = before
+			}
+		}
+		events = append(events, w.proposalEvent(round, watchEventVoteCast, proposal, vote.Voter.String(), "option="+vote.Option))
+	}
+
+	// Closed proposals.
+	ids := make([]uint32, 0, len(w.proposals))
+	for id := range w.proposals {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		proposal, err := fetchProposal(ctx, w.conn, pt, round, id)
+		if err != nil {
+			return nil, err
+		}
+		if proposal == nil || proposal.isOpen() {
+			continue
+		}
+		events = append(events, w.closedEvent(round, proposal))
+		delete(w.proposals, id)
+	}
+
+	// Supply changes.
+	for _, dev := range decoded {
+		ev, ok := dev.(*accounts.Event)
+		if !ok {
+			continue
+		}
+		switch {
+		case ev.Mint != nil:
+			events = append(events, &watchEvent{
+				Round:   round,
+				Kind:    watchEventMinted,
+				Action:  types.Mint.String(),
+				Address: ev.Mint.Owner.String(),
+				Details: helpers.FormatParaTimeDenomination(pt, ev.Mint.Amount),
+				action:  types.Mint,
+			})
+		case ev.Burn != nil:
+			events = append(events, &watchEvent{
+				Round:   round,
+				Kind:    watchEventBurned,
+				Action:  types.Burn.String(),
+				Address: ev.Burn.Owner.String(),
+				Details: helpers.FormatParaTimeDenomination(pt, ev.Burn.Amount),
+				action:  types.Burn,
+			})
+		}
+	}
+
+	// Role changes.
+	teams, err := queryRoleTeams(ctx, w.conn, pt, round)
+	if err != nil {
+		return nil, err
+	}
+	for role := types.Admin; role < types.User; role++ {
+		added, removed := diffAddresses(w.teams[role], teams[role])
+		for _, addr := range added {
+			events = append(events, &watchEvent{Round: round, Kind: watchEventRoleChanged, Action: types.SetRoles.String(), Address: addr.String(), Details: "added to " + role.String(), action: types.SetRoles})
+		}
+		for _, addr := range removed {
+			events = append(events, &watchEvent{Round: round, Kind: watchEventRoleChanged, Action: types.SetRoles.String(), Address: addr.String(), Details: "removed from " + role.String(), action: types.SetRoles})
+		}
+	}
+	w.teams = teams
+
+	// Quorum changes.
+	quorums, err := queryQuorums(ctx, w.conn, pt, round)
+	if err != nil {
+		return nil, err
+	}
+	for action := types.SetRoles; action <= types.Config; action++ {
+		if quorums[action] != w.quorums[action] {
+			events = append(events, &watchEvent{
+				Round:   round,
+				Kind:    watchEventQuorumChanged,
+				Action:  types.Config.String(),
+				Details: fmt.Sprintf("%s quorum %d%% -> %d%%", action, w.quorums[action], quorums[action]),
+				action:  types.Config,
+			})
+		}
+	}
+	w.quorums = quorums
+
+	return events, nil
+}
+
+func (w *proposalWatcher) proposalEvent(round uint64, kind string, proposal *proposalInfo, address, details string) *watchEvent {
+	id := proposal.ID
+	return &watchEvent{
+		Round:      round,
+		Kind:       kind,
+		ProposalID: &id,
+		Action:     proposal.ActionName,
+		Address:    address,
+		Details:    details,
+		action:     proposal.Raw.Action,
+	}
+}
+
+// closedEvent returns the proposal-executed or proposal-rejected event of the given closed proposal.
+// Proposals closed in any other state are reported as proposal-<state>.
+func (w *proposalWatcher) closedEvent(round uint64, proposal *proposalInfo) *watchEvent {
+	var kind string
+	switch {
+	case proposal.isExecuted():
+		kind = watchEventProposalExecuted
+	case proposal.isRejected():
+		kind = watchEventProposalRejected
+	default:
+		kind = watchEventProposalPrefix + strings.ToLower(proposal.State)
+	}
+	return w.proposalEvent(round, kind, proposal, "", "state="+proposal.State)
+}
+
+// diffAddresses returns the addresses added to and removed from the old list.
+func diffAddresses(old, new []types.Address) (added, removed []types.Address) {
+	oldSet := make(map[types.Address]bool)
+	for _, addr := range old {
+		oldSet[addr] = true
+	}
+	newSet := make(map[types.Address]bool)
+	for _, addr := range new {
+		newSet[addr] = true
+		if !oldSet[addr] {
+			added = append(added, addr)
+		}
+	}
+	for _, addr := range old {
+		if !newSet[addr] {
+			removed = append(removed, addr)
+		}
+	}
+	return
+}
+
+func init() {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.StringVar(&watchAction, "action", "", "only show events related to the given action")
+	f.Uint32Var(&watchProposalID, "proposal-id", 0, "only show events of the given proposal")
+	f.BoolVar(&watchJSON, "json", false, "print one JSON object per event")
+
+	managestWatchCmd.Flags().AddFlagSet(common.SelectorNPFlags)
+	managestWatchCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestWatchCmd.Flags().AddFlagSet(newProposalRangeFlags())
+	managestWatchCmd.Flags().AddFlagSet(f)
+
+	managestCmd.AddCommand(managestWatchCmd)
+}
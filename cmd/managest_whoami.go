@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/connection"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/helpers"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/cli/cmd/common"
+	cliConfig "github.com/oasisprotocol/cli/config"
+)
+
+// whoamiVoteUnknown is the vote option reported if it cannot be determined whether the account
+// voted, e.g. because its vote is older than --vote-lookback rounds or encrypted.
+const whoamiVoteUnknown = "unknown"
+
+// whoamiVote is the vote of an account on an open proposal it may vote on.
+type whoamiVote struct {
+	ProposalID uint32 `json:"proposal_id"`
+	Action     string `json:"action"`
+	Option     string `json:"option,omitempty"`
+}
+
+// whoamiReport is the capability summary of an account.
+type whoamiReport struct {
+	Address     types.Address     `json:"address"`
+	Name        string            `json:"name,omitempty"`
+	Round       uint64            `json:"round"`
+	Height      int64             `json:"height"`
+	Role        string            `json:"role"`
+	Initialized bool              `json:"initialized"`
+	CanPropose  []string          `json:"can_propose"`
+	CanVote     []string          `json:"can_vote"`
+	OpenVotes   []*whoamiVote     `json:"open_votes"`
+	Balances    map[string]string `json:"balances"`
+	Consensus   string            `json:"consensus_balance"`
+}
+
+// actionsForRole returns the proposal actions the given role may submit and vote on.
+func actionsForRole(role types.Role) (propose []string, vote []string, err error) {
+	propose, vote = []string{}, []string{}
+	for action := types.SetRoles; action <= types.Config; action++ {
+		proposerRole, err := proposerRoleForAction(action)
+		if err != nil {
+			return nil, nil, err
+		}
+		if proposerRole == role {
+			propose = append(propose, action.String())
+		}
+
+		voterRole, err := voterRoleForAction(action)
+		if err != nil {
+			return nil, nil, err
+		}
+		if voterRole == role {
+			vote = append(vote, action.String())
+		}
+	}
+	return propose, vote, nil
+}
+
+var managestWhoamiCmd = &cobra.Command{
+	Use:   "whoami [address]",
+	Short: "Show what the selected account or given address may do",
+	Long: "Show the runtime role and init status of the selected account (or the given account or address), " +
+		"the proposal actions it may submit and vote on, its votes on open proposals it may vote on and " +
+		"its balances.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cliConfig.Global()
+		npa := common.GetNPASelection(cfg)
+		format := common.GetOutputFormat()
+
+		if npa.ParaTime == nil {
+			cobra.CheckErr("no runtime configured")
+		}
+
+		var target string
+		switch {
+		case len(args) > 0:
+			target = args[0]
+		case npa.Account != nil:
+			target = npa.Account.Address
+		default:
+			cobra.CheckErr("no address given and no wallet configured")
+		}
+		addr, err := common.ResolveLocalAccountOrAddress(npa.Network, target)
+		cobra.CheckErr(err)
+
+		// Establish connection with the target network.
+		ctx := context.Background()
+		conn, err := connection.Connect(ctx, npa.Network)
+		cobra.CheckErr(err)
+
+		// Resolve the runtime round and the consensus height from the same selection.
+		round, height, err := common.GetActualRoundAndHeight(ctx, conn, npa.ParaTime)
+		cobra.CheckErr(err)
+
+		accounts := conn.Runtime(npa.ParaTime).Accounts
+		role, err := accounts.Role(ctx, round, *addr)
+		cobra.CheckErr(err)
+		initialized, err := accounts.InitInfo(ctx, round, *addr)
+		cobra.CheckErr(err)
+
+		report := &whoamiReport{
+			Address:     *addr,
+			Name:        common.FindAccountName(cfg, addr.String()),
+			Round:       round,
+			Height:      height,
+			Role:        role.String(),
+			Initialized: initialized,
+			OpenVotes:   []*whoamiVote{},
+			Balances:    make(map[string]string),
+		}
+		report.CanPropose, report.CanVote, err = actionsForRole(role)
+		cobra.CheckErr(err)
+
+		// Check the votes on open proposals the account may vote on.
+		latestID, err := accounts.ProposalIDInfo(ctx, round)
+		cobra.CheckErr(err)
+		for id := proposalRangeStart(cmd, latestID); id <= latestID; id++ {
+			proposal, err := fetchProposal(ctx, conn, npa.ParaTime, round, id)
+			cobra.CheckErr(err)
+			if proposal == nil || !proposal.isOpen() {
+				continue
+			}
+			voterRole, err := voterRoleForAction(proposal.Raw.Action)
+			cobra.CheckErr(err)
+			if voterRole != role {
+				continue
+			}
+
+			votes, err := scanProposalVotes(ctx, conn, npa.ParaTime, proposal, round)
+			cobra.CheckErr(err)
+			option, known := votes.lookup(*addr)
+			if !known {
+				option = whoamiVoteUnknown
+			}
+			report.OpenVotes = append(report.OpenVotes, &whoamiVote{
+				ProposalID: proposal.ID,
+				Action:     proposal.ActionName,
+				Option:     option,
+			})
+		}
+
+		// Query the runtime and consensus balances.
+		rtBalances, err := accounts.Balances(ctx, round, *addr)
+		cobra.CheckErr(err)
+		for denom, balance := range rtBalances.Balances {
+			report.Balances[string(denom)] = helpers.FormatParaTimeDenomination(npa.ParaTime, types.NewBaseUnits(balance, denom))
+		}
+		if _, ok := report.Balances[string(types.NativeDenomination)]; !ok {
+			report.Balances[string(types.NativeDenomination)] = helpers.FormatParaTimeDenomination(npa.ParaTime, types.NewBaseUnits(types.Quantity{}, types.NativeDenomination))
+		}
+
+		consensusAccount, err := conn.Consensus().Staking().Account(ctx, &staking.OwnerQuery{
+			Owner:  addr.ConsensusAddress(),
+			Height: height,
+		})
+		cobra.CheckErr(err)
+		report.Consensus = helpers.FormatConsensusDenomination(npa.Network, consensusAccount.General.Balance)
+
+		if format != common.FormatTable {
+			common.PrintStructured(format, report)
+			return
+		}
+
+		fmt.Printf("Address:     %s\n", report.Address)
+		if report.Name != "" {
+			fmt.Printf("Account:     %s\n", report.Name)
+		}
+		fmt.Printf("Round:       %d\n", report.Round)
+		fmt.Printf("Height:      %d\n", report.Height)
+		fmt.Printf("Role:        %s\n", report.Role)
+		fmt.Printf("Initialized: %t\n", report.Initialized)
+		fmt.Printf("May propose: %s\n", formatActionList(report.CanPropose))
+		fmt.Printf("May vote on: %s\n", formatActionList(report.CanVote))
+		fmt.Println()
+
+		if len(report.OpenVotes) == 0 {
+			fmt.Println("No open proposals to vote on.")
+		} else {
+			output := make([][]string, 0, len(report.OpenVotes))
+			for _, vote := range report.OpenVotes {
+				option := vote.Option
+				if option == "" {
+					option = "(not voted)"
+				}
+				output = append(output, []string{fmt.Sprintf("%d", vote.ProposalID), vote.Action, option})
+			}
+			fmt.Println("Open proposals:")
+			renderTable([]string{"ID", "Action", "Vote"}, output)
+		}
+		fmt.Println()
+
+		fmt.Printf("=== EXECUTION LAYER (%s) ===\n", npa.ParaTimeName)
+		for _, denom := range sortedKeys(report.Balances) {
+			fmt.Printf("  %s\n", report.Balances[denom])
+		}
+		fmt.Printf("=== CONSENSUS LAYER (%s) ===\n", npa.NetworkName)
+		fmt.Printf("  %s\n", report.Consensus)
+	},
+}
+
+// formatActionList formats a list of proposal actions.
+func formatActionList(actions []string) string {
+	if len(actions) == 0 {
+		return "(none)"
+	}
+	return strings.Join(actions, ", ")
+}
+
+func init() {
+	managestWhoamiCmd.Flags().AddFlagSet(common.SelectorNPFlags)
+	managestWhoamiCmd.Flags().AddFlagSet(common.RoundFlag)
+	managestWhoamiCmd.Flags().AddFlagSet(common.FormatFlag)
+	managestWhoamiCmd.Flags().AddFlagSet(newProposalRangeFlags())
+	managestWhoamiCmd.Flags().AddFlagSet(newVoteScanFlags())
+
+	managestCmd.AddCommand(managestWhoamiCmd)
+}
@@ -112,4 +112,5 @@ func init() {
 	rootCmd.AddCommand(inspect.Cmd)
 	rootCmd.AddCommand(txCmd)
 	rootCmd.AddCommand(managestCmd)
+	rootCmd.AddCommand(faucetCmd)
 }
@@ -48,6 +48,7 @@ type Config struct {
 	Networks    config.Networks `mapstructure:"networks"`
 	Wallet      Wallet          `mapstructure:"wallets"`
 	AddressBook AddressBook     `mapstructure:"address_book"`
+	FeeLimits   FeeLimits       `mapstructure:"fee_limits"`
 }
 
 // Load loads the configuration structure from viper.
@@ -169,5 +170,8 @@ func (cfg *Config) Validate() error {
 	if err := cfg.Wallet.Validate(); err != nil {
 		return fmt.Errorf("failed to validate wallet configuration: %w", err)
 	}
+	if err := cfg.FeeLimits.Validate(); err != nil {
+		return fmt.Errorf("failed to validate fee limit configuration: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/config"
+)
+
+// FeeLimits contains the configuration of the per-network transaction fee limits.
+type FeeLimits struct {
+	// All is a map of fee limits keyed by network name.
+	All map[string]*FeeLimit `mapstructure:",remain"`
+}
+
+// Validate performs config validation.
+func (fl *FeeLimits) Validate() error {
+	for name, l := range fl.All {
+		if err := config.ValidateIdentifier(name); err != nil {
+			return fmt.Errorf("malformed network name '%s': %w", name, err)
+		}
+
+		if err := l.Validate(); err != nil {
+			return fmt.Errorf("network '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// FeeLimit contains the maximum transaction fees of a single network. All amounts are given in
+// display units (e.g. "0.5").
+type FeeLimit struct {
+	// Consensus is the maximum fee of consensus layer transactions.
+	Consensus string `mapstructure:"consensus,omitempty"`
+
+	// ParaTime is a map of the maximum fees of ParaTime transactions keyed by the symbol of the
+	// denomination the fee is paid in.
+	ParaTime map[string]string `mapstructure:"paratime,omitempty"`
+}
+
+// Validate performs config validation.
+func (l *FeeLimit) Validate() error {
+	if l == nil {
+		return nil
+	}
+	if err := validateFeeAmount(l.Consensus); err != nil {
+		return fmt.Errorf("consensus fee limit: %w", err)
+	}
+	for denom, amount := range l.ParaTime {
+		if err := validateFeeAmount(amount); err != nil {
+			return fmt.Errorf("paratime fee limit for '%s': %w", denom, err)
+		}
+	}
+	return nil
+}
+
+func validateFeeAmount(amount string) error {
+	if amount == "" {
+		return nil
+	}
+	v, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return fmt.Errorf("malformed amount '%s'", amount)
+	}
+	if v.Sign() < 0 {
+		return fmt.Errorf("amount '%s' must not be negative", amount)
+	}
+	return nil
+}